@@ -14,10 +14,6 @@ type TemplateInitParameters struct {
 	// (String) The JSON body of the template.
 	// The JSON body of the template.
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
-
-	// (String) Name of the component template to create.
-	// Name of the component template to create.
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 type TemplateObservation struct {
@@ -28,10 +24,6 @@ type TemplateObservation struct {
 
 	// (String) The ID of this resource.
 	ID *string `json:"id,omitempty" tf:"id,omitempty"`
-
-	// (String) Name of the component template to create.
-	// Name of the component template to create.
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 type TemplateParameters struct {
@@ -40,11 +32,6 @@ type TemplateParameters struct {
 	// The JSON body of the template.
 	// +kubebuilder:validation:Optional
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
-
-	// (String) Name of the component template to create.
-	// Name of the component template to create.
-	// +kubebuilder:validation:Optional
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 // TemplateSpec defines the desired state of Template
@@ -84,7 +71,6 @@ type Template struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.body) || (has(self.initProvider) && has(self.initProvider.body))",message="spec.forProvider.body is a required parameter"
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name) || (has(self.initProvider) && has(self.initProvider.name))",message="spec.forProvider.name is a required parameter"
 	Spec   TemplateSpec   `json:"spec"`
 	Status TemplateStatus `json:"status,omitempty"`
 }
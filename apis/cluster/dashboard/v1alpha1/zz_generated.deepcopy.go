@@ -217,11 +217,6 @@ func (in *TenantInitParameters) DeepCopyInto(out *TenantInitParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.TenantName != nil {
-		in, out := &in.TenantName, &out.TenantName
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantInitParameters.
@@ -284,11 +279,6 @@ func (in *TenantObservation) DeepCopyInto(out *TenantObservation) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.TenantName != nil {
-		in, out := &in.TenantName, &out.TenantName
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantObservation.
@@ -309,11 +299,6 @@ func (in *TenantParameters) DeepCopyInto(out *TenantParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.TenantName != nil {
-		in, out := &in.TenantName, &out.TenantName
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantParameters.
@@ -38,11 +38,6 @@ func (in *Stream) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StreamInitParameters) DeepCopyInto(out *StreamInitParameters) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamInitParameters.
@@ -95,11 +90,6 @@ func (in *StreamObservation) DeepCopyInto(out *StreamObservation) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamObservation.
@@ -115,11 +105,6 @@ func (in *StreamObservation) DeepCopy() *StreamObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StreamParameters) DeepCopyInto(out *StreamParameters) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamParameters.
@@ -136,8 +121,8 @@ func (in *StreamParameters) DeepCopy() *StreamParameters {
 func (in *StreamSpec) DeepCopyInto(out *StreamSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
-	in.ForProvider.DeepCopyInto(&out.ForProvider)
-	in.InitProvider.DeepCopyInto(&out.InitProvider)
+	out.ForProvider = in.ForProvider
+	out.InitProvider = in.InitProvider
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamSpec.
@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -43,11 +44,6 @@ func (in *PolicyInitParameters) DeepCopyInto(out *PolicyInitParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.PolicyID != nil {
-		in, out := &in.PolicyID, &out.PolicyID
-		*out = new(string)
-		**out = **in
-	}
 	if in.PrimaryTerm != nil {
 		in, out := &in.PrimaryTerm, &out.PrimaryTerm
 		*out = new(float64)
@@ -180,6 +176,16 @@ func (in *PolicyMappingInitParameters) DeepCopyInto(out *PolicyMappingInitParame
 		*out = new(string)
 		**out = **in
 	}
+	if in.PolicyIDRef != nil {
+		in, out := &in.PolicyIDRef, &out.PolicyIDRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyIDSelector != nil {
+		in, out := &in.PolicyIDSelector, &out.PolicyIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.State != nil {
 		in, out := &in.State, &out.State
 		*out = new(string)
@@ -353,6 +359,16 @@ func (in *PolicyMappingParameters) DeepCopyInto(out *PolicyMappingParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PolicyIDRef != nil {
+		in, out := &in.PolicyIDRef, &out.PolicyIDRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyIDSelector != nil {
+		in, out := &in.PolicyIDSelector, &out.PolicyIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.State != nil {
 		in, out := &in.State, &out.State
 		*out = new(string)
@@ -418,11 +434,6 @@ func (in *PolicyObservation) DeepCopyInto(out *PolicyObservation) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.PolicyID != nil {
-		in, out := &in.PolicyID, &out.PolicyID
-		*out = new(string)
-		**out = **in
-	}
 	if in.PrimaryTerm != nil {
 		in, out := &in.PrimaryTerm, &out.PrimaryTerm
 		*out = new(float64)
@@ -453,11 +464,6 @@ func (in *PolicyParameters) DeepCopyInto(out *PolicyParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.PolicyID != nil {
-		in, out := &in.PolicyID, &out.PolicyID
-		*out = new(string)
-		**out = **in
-	}
 	if in.PrimaryTerm != nil {
 		in, out := &in.PrimaryTerm, &out.PrimaryTerm
 		*out = new(float64)
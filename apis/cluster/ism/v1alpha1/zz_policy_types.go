@@ -15,10 +15,6 @@ type PolicyInitParameters struct {
 	// The policy document.
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
 
-	// (String) The id of the ISM policy.
-	// The id of the ISM policy.
-	PolicyID *string `json:"policyId,omitempty" tf:"policy_id,omitempty"`
-
 	// (Number) The primary term of the ISM policy version.
 	// The primary term of the ISM policy version.
 	PrimaryTerm *float64 `json:"primaryTerm,omitempty" tf:"primary_term,omitempty"`
@@ -37,10 +33,6 @@ type PolicyObservation struct {
 	// (String) The ID of this resource.
 	ID *string `json:"id,omitempty" tf:"id,omitempty"`
 
-	// (String) The id of the ISM policy.
-	// The id of the ISM policy.
-	PolicyID *string `json:"policyId,omitempty" tf:"policy_id,omitempty"`
-
 	// (Number) The primary term of the ISM policy version.
 	// The primary term of the ISM policy version.
 	PrimaryTerm *float64 `json:"primaryTerm,omitempty" tf:"primary_term,omitempty"`
@@ -57,11 +49,6 @@ type PolicyParameters struct {
 	// +kubebuilder:validation:Optional
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
 
-	// (String) The id of the ISM policy.
-	// The id of the ISM policy.
-	// +kubebuilder:validation:Optional
-	PolicyID *string `json:"policyId,omitempty" tf:"policy_id,omitempty"`
-
 	// (Number) The primary term of the ISM policy version.
 	// The primary term of the ISM policy version.
 	// +kubebuilder:validation:Optional
@@ -110,7 +97,6 @@ type Policy struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.body) || (has(self.initProvider) && has(self.initProvider.body))",message="spec.forProvider.body is a required parameter"
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.policyId) || (has(self.initProvider) && has(self.initProvider.policyId))",message="spec.forProvider.policyId is a required parameter"
 	Spec   PolicySpec   `json:"spec"`
 	Status PolicyStatus `json:"status,omitempty"`
 }
@@ -114,6 +114,31 @@ func (in *IndexInitParameters) DeepCopyInto(out *IndexInitParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.DefaultPipelineRef != nil {
+		in, out := &in.DefaultPipelineRef, &out.DefaultPipelineRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultPipelineSelector != nil {
+		in, out := &in.DefaultPipelineSelector, &out.DefaultPipelineSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FinalPipeline != nil {
+		in, out := &in.FinalPipeline, &out.FinalPipeline
+		*out = new(string)
+		**out = **in
+	}
+	if in.FinalPipelineRef != nil {
+		in, out := &in.FinalPipelineRef, &out.FinalPipelineRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FinalPipelineSelector != nil {
+		in, out := &in.FinalPipelineSelector, &out.FinalPipelineSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ForceDestroy != nil {
 		in, out := &in.ForceDestroy, &out.ForceDestroy
 		*out = new(bool)
@@ -239,11 +264,6 @@ func (in *IndexInitParameters) DeepCopyInto(out *IndexInitParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
 	if in.NumberOfReplicas != nil {
 		in, out := &in.NumberOfReplicas, &out.NumberOfReplicas
 		*out = new(string)
@@ -471,6 +491,11 @@ func (in *IndexObservation) DeepCopyInto(out *IndexObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FinalPipeline != nil {
+		in, out := &in.FinalPipeline, &out.FinalPipeline
+		*out = new(string)
+		**out = **in
+	}
 	if in.ForceDestroy != nil {
 		in, out := &in.ForceDestroy, &out.ForceDestroy
 		*out = new(bool)
@@ -601,11 +626,6 @@ func (in *IndexObservation) DeepCopyInto(out *IndexObservation) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
 	if in.NumberOfReplicas != nil {
 		in, out := &in.NumberOfReplicas, &out.NumberOfReplicas
 		*out = new(string)
@@ -801,6 +821,31 @@ func (in *IndexParameters) DeepCopyInto(out *IndexParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.DefaultPipelineRef != nil {
+		in, out := &in.DefaultPipelineRef, &out.DefaultPipelineRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultPipelineSelector != nil {
+		in, out := &in.DefaultPipelineSelector, &out.DefaultPipelineSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FinalPipeline != nil {
+		in, out := &in.FinalPipeline, &out.FinalPipeline
+		*out = new(string)
+		**out = **in
+	}
+	if in.FinalPipelineRef != nil {
+		in, out := &in.FinalPipelineRef, &out.FinalPipelineRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FinalPipelineSelector != nil {
+		in, out := &in.FinalPipelineSelector, &out.FinalPipelineSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ForceDestroy != nil {
 		in, out := &in.ForceDestroy, &out.ForceDestroy
 		*out = new(bool)
@@ -926,11 +971,6 @@ func (in *IndexParameters) DeepCopyInto(out *IndexParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
 	if in.NumberOfReplicas != nil {
 		in, out := &in.NumberOfReplicas, &out.NumberOfReplicas
 		*out = new(string)
@@ -1487,11 +1527,6 @@ func (in *RoleInitParameters) DeepCopyInto(out *RoleInitParameters) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.RoleName != nil {
-		in, out := &in.RoleName, &out.RoleName
-		*out = new(string)
-		**out = **in
-	}
 	if in.TenantPermissions != nil {
 		in, out := &in.TenantPermissions, &out.TenantPermissions
 		*out = make([]TenantPermissionsInitParameters, len(*in))
@@ -1574,11 +1609,6 @@ func (in *RoleObservation) DeepCopyInto(out *RoleObservation) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.RoleName != nil {
-		in, out := &in.RoleName, &out.RoleName
-		*out = new(string)
-		**out = **in
-	}
 	if in.TenantPermissions != nil {
 		in, out := &in.TenantPermissions, &out.TenantPermissions
 		*out = make([]TenantPermissionsObservation, len(*in))
@@ -1624,11 +1654,6 @@ func (in *RoleParameters) DeepCopyInto(out *RoleParameters) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.RoleName != nil {
-		in, out := &in.RoleName, &out.RoleName
-		*out = new(string)
-		**out = **in
-	}
 	if in.TenantPermissions != nil {
 		in, out := &in.TenantPermissions, &out.TenantPermissions
 		*out = make([]TenantPermissionsParameters, len(*in))
@@ -1718,11 +1743,6 @@ func (in *ScriptInitParameters) DeepCopyInto(out *ScriptInitParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.ScriptID != nil {
-		in, out := &in.ScriptID, &out.ScriptID
-		*out = new(string)
-		**out = **in
-	}
 	if in.Source != nil {
 		in, out := &in.Source, &out.Source
 		*out = new(string)
@@ -1785,11 +1805,6 @@ func (in *ScriptObservation) DeepCopyInto(out *ScriptObservation) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.ScriptID != nil {
-		in, out := &in.ScriptID, &out.ScriptID
-		*out = new(string)
-		**out = **in
-	}
 	if in.Source != nil {
 		in, out := &in.Source, &out.Source
 		*out = new(string)
@@ -1815,11 +1830,6 @@ func (in *ScriptParameters) DeepCopyInto(out *ScriptParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.ScriptID != nil {
-		in, out := &in.ScriptID, &out.ScriptID
-		*out = new(string)
-		**out = **in
-	}
 	if in.Source != nil {
 		in, out := &in.Source, &out.Source
 		*out = new(string)
@@ -2055,11 +2065,6 @@ func (in *UserInitParameters) DeepCopyInto(out *UserInitParameters) {
 		*out = new(v1.SecretKeySelector)
 		**out = **in
 	}
-	if in.Username != nil {
-		in, out := &in.Username, &out.Username
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserInitParameters.
@@ -2144,11 +2149,6 @@ func (in *UserObservation) DeepCopyInto(out *UserObservation) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Username != nil {
-		in, out := &in.Username, &out.Username
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserObservation.
@@ -2206,11 +2206,6 @@ func (in *UserParameters) DeepCopyInto(out *UserParameters) {
 		*out = new(v1.SecretKeySelector)
 		**out = **in
 	}
-	if in.Username != nil {
-		in, out := &in.Username, &out.Username
-		*out = new(string)
-		**out = **in
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserParameters.
@@ -110,10 +110,6 @@ type RoleInitParameters struct {
 	// A configuration of index permissions
 	IndexPermissions []IndexPermissionsInitParameters `json:"indexPermissions,omitempty" tf:"index_permissions,omitempty"`
 
-	// (String) The name of the security role.
-	// The name of the security role.
-	RoleName *string `json:"roleName,omitempty" tf:"role_name,omitempty"`
-
 	// (Block Set) A configuration of tenant permissions (see below for nested schema)
 	// A configuration of tenant permissions
 	TenantPermissions []TenantPermissionsInitParameters `json:"tenantPermissions,omitempty" tf:"tenant_permissions,omitempty"`
@@ -137,10 +133,6 @@ type RoleObservation struct {
 	// A configuration of index permissions
 	IndexPermissions []IndexPermissionsObservation `json:"indexPermissions,omitempty" tf:"index_permissions,omitempty"`
 
-	// (String) The name of the security role.
-	// The name of the security role.
-	RoleName *string `json:"roleName,omitempty" tf:"role_name,omitempty"`
-
 	// (Block Set) A configuration of tenant permissions (see below for nested schema)
 	// A configuration of tenant permissions
 	TenantPermissions []TenantPermissionsObservation `json:"tenantPermissions,omitempty" tf:"tenant_permissions,omitempty"`
@@ -164,11 +156,6 @@ type RoleParameters struct {
 	// +kubebuilder:validation:Optional
 	IndexPermissions []IndexPermissionsParameters `json:"indexPermissions,omitempty" tf:"index_permissions,omitempty"`
 
-	// (String) The name of the security role.
-	// The name of the security role.
-	// +kubebuilder:validation:Optional
-	RoleName *string `json:"roleName,omitempty" tf:"role_name,omitempty"`
-
 	// (Block Set) A configuration of tenant permissions (see below for nested schema)
 	// A configuration of tenant permissions
 	// +kubebuilder:validation:Optional
@@ -252,9 +239,8 @@ type RoleStatus struct {
 type Role struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.roleName) || (has(self.initProvider) && has(self.initProvider.roleName))",message="spec.forProvider.roleName is a required parameter"
-	Spec   RoleSpec   `json:"spec"`
-	Status RoleStatus `json:"status,omitempty"`
+	Spec              RoleSpec   `json:"spec"`
+	Status            RoleStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
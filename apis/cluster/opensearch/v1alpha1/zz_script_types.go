@@ -15,10 +15,6 @@ type ScriptInitParameters struct {
 	// Specifies the language the script is written in. Defaults to painless.
 	Lang *string `json:"lang,omitempty" tf:"lang,omitempty"`
 
-	// (String) Identifier for the stored script. Must be unique within the cluster.
-	// Identifier for the stored script. Must be unique within the cluster.
-	ScriptID *string `json:"scriptId,omitempty" tf:"script_id,omitempty"`
-
 	// (String) The source of the stored script
 	// The source of the stored script
 	Source *string `json:"source,omitempty" tf:"source,omitempty"`
@@ -33,10 +29,6 @@ type ScriptObservation struct {
 	// Specifies the language the script is written in. Defaults to painless.
 	Lang *string `json:"lang,omitempty" tf:"lang,omitempty"`
 
-	// (String) Identifier for the stored script. Must be unique within the cluster.
-	// Identifier for the stored script. Must be unique within the cluster.
-	ScriptID *string `json:"scriptId,omitempty" tf:"script_id,omitempty"`
-
 	// (String) The source of the stored script
 	// The source of the stored script
 	Source *string `json:"source,omitempty" tf:"source,omitempty"`
@@ -49,11 +41,6 @@ type ScriptParameters struct {
 	// +kubebuilder:validation:Optional
 	Lang *string `json:"lang,omitempty" tf:"lang,omitempty"`
 
-	// (String) Identifier for the stored script. Must be unique within the cluster.
-	// Identifier for the stored script. Must be unique within the cluster.
-	// +kubebuilder:validation:Optional
-	ScriptID *string `json:"scriptId,omitempty" tf:"script_id,omitempty"`
-
 	// (String) The source of the stored script
 	// The source of the stored script
 	// +kubebuilder:validation:Optional
@@ -96,7 +83,6 @@ type ScriptStatus struct {
 type Script struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.scriptId) || (has(self.initProvider) && has(self.initProvider.scriptId))",message="spec.forProvider.scriptId is a required parameter"
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.source) || (has(self.initProvider) && has(self.initProvider.source))",message="spec.forProvider.source is a required parameter"
 	Spec   ScriptSpec   `json:"spec"`
 	Status ScriptStatus `json:"status,omitempty"`
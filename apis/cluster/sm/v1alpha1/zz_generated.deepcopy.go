@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -43,16 +44,26 @@ func (in *PolicyInitParameters) DeepCopyInto(out *PolicyInitParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.PolicyName != nil {
-		in, out := &in.PolicyName, &out.PolicyName
-		*out = new(string)
-		**out = **in
-	}
 	if in.PrimaryTerm != nil {
 		in, out := &in.PrimaryTerm, &out.PrimaryTerm
 		*out = new(float64)
 		**out = **in
 	}
+	if in.Repository != nil {
+		in, out := &in.Repository, &out.Repository
+		*out = new(string)
+		**out = **in
+	}
+	if in.RepositoryRef != nil {
+		in, out := &in.RepositoryRef, &out.RepositoryRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RepositorySelector != nil {
+		in, out := &in.RepositorySelector, &out.RepositorySelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SeqNo != nil {
 		in, out := &in.SeqNo, &out.SeqNo
 		*out = new(float64)
@@ -115,16 +126,16 @@ func (in *PolicyObservation) DeepCopyInto(out *PolicyObservation) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.PolicyName != nil {
-		in, out := &in.PolicyName, &out.PolicyName
-		*out = new(string)
-		**out = **in
-	}
 	if in.PrimaryTerm != nil {
 		in, out := &in.PrimaryTerm, &out.PrimaryTerm
 		*out = new(float64)
 		**out = **in
 	}
+	if in.Repository != nil {
+		in, out := &in.Repository, &out.Repository
+		*out = new(string)
+		**out = **in
+	}
 	if in.SeqNo != nil {
 		in, out := &in.SeqNo, &out.SeqNo
 		*out = new(float64)
@@ -150,16 +161,26 @@ func (in *PolicyParameters) DeepCopyInto(out *PolicyParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.PolicyName != nil {
-		in, out := &in.PolicyName, &out.PolicyName
-		*out = new(string)
-		**out = **in
-	}
 	if in.PrimaryTerm != nil {
 		in, out := &in.PrimaryTerm, &out.PrimaryTerm
 		*out = new(float64)
 		**out = **in
 	}
+	if in.Repository != nil {
+		in, out := &in.Repository, &out.Repository
+		*out = new(string)
+		**out = **in
+	}
+	if in.RepositoryRef != nil {
+		in, out := &in.RepositoryRef, &out.RepositoryRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RepositorySelector != nil {
+		in, out := &in.RepositorySelector, &out.RepositorySelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SeqNo != nil {
 		in, out := &in.SeqNo, &out.SeqNo
 		*out = new(float64)
@@ -0,0 +1,56 @@
+// Code generated by angryjet. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	reference "github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	errors "github.com/pkg/errors"
+	v1alpha1 "github.com/tagesjump/provider-opensearch/apis/namespaced/component/v1alpha1"
+	common "github.com/tagesjump/provider-opensearch/config/common"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveReferences of this IndexTemplate.
+func (mg *IndexTemplate) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var mrsp reference.MultiNamespacedResolutionResponse
+	var err error
+
+	mrsp, err = r.ResolveMultiple(ctx, reference.MultiNamespacedResolutionRequest{
+		CurrentValues: reference.FromPtrValues(mg.Spec.ForProvider.ComposedOf),
+		Extract:       common.ExternalNameIfReady(),
+		Namespace:     mg.GetNamespace(),
+		References:    mg.Spec.ForProvider.ComposedOfRefs,
+		Selector:      mg.Spec.ForProvider.ComposedOfSelector,
+		To: reference.To{
+			List:    &v1alpha1.TemplateList{},
+			Managed: &v1alpha1.Template{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.ComposedOf")
+	}
+	mg.Spec.ForProvider.ComposedOf = reference.ToPtrValues(mrsp.ResolvedValues)
+	mg.Spec.ForProvider.ComposedOfRefs = mrsp.ResolvedReferences
+
+	mrsp, err = r.ResolveMultiple(ctx, reference.MultiNamespacedResolutionRequest{
+		CurrentValues: reference.FromPtrValues(mg.Spec.InitProvider.ComposedOf),
+		Extract:       common.ExternalNameIfReady(),
+		Namespace:     mg.GetNamespace(),
+		References:    mg.Spec.InitProvider.ComposedOfRefs,
+		Selector:      mg.Spec.InitProvider.ComposedOfSelector,
+		To: reference.To{
+			List:    &v1alpha1.TemplateList{},
+			Managed: &v1alpha1.Template{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.InitProvider.ComposedOf")
+	}
+	mg.Spec.InitProvider.ComposedOf = reference.ToPtrValues(mrsp.ResolvedValues)
+	mg.Spec.InitProvider.ComposedOfRefs = mrsp.ResolvedReferences
+
+	return nil
+}
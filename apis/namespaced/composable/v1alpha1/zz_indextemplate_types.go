@@ -16,9 +16,18 @@ type IndexTemplateInitParameters struct {
 	// The JSON body of the index template.
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
 
-	// (String) The name of the index template.
-	// The name of the index template.
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
+	// The component templates the template is composed of. They are appended to composed_of of the body, unless it already names them.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/component/v1alpha1.Template
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
+	ComposedOf []*string `json:"composedOf,omitempty" tf:"composed_of,omitempty"`
+
+	// References to Template in component to populate composedOf.
+	// +kubebuilder:validation:Optional
+	ComposedOfRefs []v1.NamespacedReference `json:"composedOfRefs,omitempty" tf:"-"`
+
+	// Selector for a list of Template in component to populate composedOf.
+	// +kubebuilder:validation:Optional
+	ComposedOfSelector *v1.NamespacedSelector `json:"composedOfSelector,omitempty" tf:"-"`
 }
 
 type IndexTemplateObservation struct {
@@ -27,12 +36,11 @@ type IndexTemplateObservation struct {
 	// The JSON body of the index template.
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
 
+	// The component templates the template is composed of. They are appended to composed_of of the body, unless it already names them.
+	ComposedOf []*string `json:"composedOf,omitempty" tf:"composed_of,omitempty"`
+
 	// (String) The ID of this resource.
 	ID *string `json:"id,omitempty" tf:"id,omitempty"`
-
-	// (String) The name of the index template.
-	// The name of the index template.
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 type IndexTemplateParameters struct {
@@ -42,10 +50,19 @@ type IndexTemplateParameters struct {
 	// +kubebuilder:validation:Optional
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
 
-	// (String) The name of the index template.
-	// The name of the index template.
+	// The component templates the template is composed of. They are appended to composed_of of the body, unless it already names them.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/component/v1alpha1.Template
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
+	// +kubebuilder:validation:Optional
+	ComposedOf []*string `json:"composedOf,omitempty" tf:"composed_of,omitempty"`
+
+	// References to Template in component to populate composedOf.
+	// +kubebuilder:validation:Optional
+	ComposedOfRefs []v1.NamespacedReference `json:"composedOfRefs,omitempty" tf:"-"`
+
+	// Selector for a list of Template in component to populate composedOf.
 	// +kubebuilder:validation:Optional
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
+	ComposedOfSelector *v1.NamespacedSelector `json:"composedOfSelector,omitempty" tf:"-"`
 }
 
 // IndexTemplateSpec defines the desired state of IndexTemplate
@@ -85,7 +102,6 @@ type IndexTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.body) || (has(self.initProvider) && has(self.initProvider.body))",message="spec.forProvider.body is a required parameter"
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name) || (has(self.initProvider) && has(self.initProvider.name))",message="spec.forProvider.name is a required parameter"
 	Spec   IndexTemplateSpec   `json:"spec"`
 	Status IndexTemplateStatus `json:"status,omitempty"`
 }
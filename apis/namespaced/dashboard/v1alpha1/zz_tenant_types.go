@@ -15,10 +15,6 @@ type TenantInitParameters struct {
 	// (String) Description of the tenant.
 	// Description of the tenant.
 	Description *string `json:"description,omitempty" tf:"description,omitempty"`
-
-	// (String) The name of the tenant.
-	// The name of the tenant.
-	TenantName *string `json:"tenantName,omitempty" tf:"tenant_name,omitempty"`
 }
 
 type TenantObservation struct {
@@ -32,10 +28,6 @@ type TenantObservation struct {
 
 	// (String)
 	Index *string `json:"index,omitempty" tf:"index,omitempty"`
-
-	// (String) The name of the tenant.
-	// The name of the tenant.
-	TenantName *string `json:"tenantName,omitempty" tf:"tenant_name,omitempty"`
 }
 
 type TenantParameters struct {
@@ -44,11 +36,6 @@ type TenantParameters struct {
 	// Description of the tenant.
 	// +kubebuilder:validation:Optional
 	Description *string `json:"description,omitempty" tf:"description,omitempty"`
-
-	// (String) The name of the tenant.
-	// The name of the tenant.
-	// +kubebuilder:validation:Optional
-	TenantName *string `json:"tenantName,omitempty" tf:"tenant_name,omitempty"`
 }
 
 // TenantSpec defines the desired state of Tenant
@@ -87,9 +74,8 @@ type TenantStatus struct {
 type Tenant struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.tenantName) || (has(self.initProvider) && has(self.initProvider.tenantName))",message="spec.forProvider.tenantName is a required parameter"
-	Spec   TenantSpec   `json:"spec"`
-	Status TenantStatus `json:"status,omitempty"`
+	Spec              TenantSpec   `json:"spec"`
+	Status            TenantStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -11,28 +11,15 @@ import (
 )
 
 type StreamInitParameters struct {
-
-	// (String) Name of the data stream to create, must have a matching
-	// Name of the data stream to create, must have a matching
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 type StreamObservation struct {
 
 	// (String) The ID of this resource.
 	ID *string `json:"id,omitempty" tf:"id,omitempty"`
-
-	// (String) Name of the data stream to create, must have a matching
-	// Name of the data stream to create, must have a matching
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 type StreamParameters struct {
-
-	// (String) Name of the data stream to create, must have a matching
-	// Name of the data stream to create, must have a matching
-	// +kubebuilder:validation:Optional
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 // StreamSpec defines the desired state of Stream
@@ -71,9 +58,8 @@ type StreamStatus struct {
 type Stream struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name) || (has(self.initProvider) && has(self.initProvider.name))",message="spec.forProvider.name is a required parameter"
-	Spec   StreamSpec   `json:"spec"`
-	Status StreamStatus `json:"status,omitempty"`
+	Spec              StreamSpec   `json:"spec"`
+	Status            StreamStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -15,10 +15,6 @@ type PipelineInitParameters struct {
 	// (String) The JSON body of the ingest pipeline
 	// The JSON body of the ingest pipeline
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
-
-	// (String) The name of the ingest pipeline
-	// The name of the ingest pipeline
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 type PipelineObservation struct {
@@ -29,10 +25,6 @@ type PipelineObservation struct {
 
 	// (String) The ID of this resource.
 	ID *string `json:"id,omitempty" tf:"id,omitempty"`
-
-	// (String) The name of the ingest pipeline
-	// The name of the ingest pipeline
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 type PipelineParameters struct {
@@ -41,11 +33,6 @@ type PipelineParameters struct {
 	// The JSON body of the ingest pipeline
 	// +kubebuilder:validation:Optional
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
-
-	// (String) The name of the ingest pipeline
-	// The name of the ingest pipeline
-	// +kubebuilder:validation:Optional
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 }
 
 // PipelineSpec defines the desired state of Pipeline
@@ -85,7 +72,6 @@ type Pipeline struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.body) || (has(self.initProvider) && has(self.initProvider.body))",message="spec.forProvider.body is a required parameter"
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name) || (has(self.initProvider) && has(self.initProvider.name))",message="spec.forProvider.name is a required parameter"
 	Spec   PipelineSpec   `json:"spec"`
 	Status PipelineStatus `json:"status,omitempty"`
 }
@@ -29,8 +29,18 @@ type PolicyMappingInitParameters struct {
 
 	// (String) The name of the policy.
 	// The name of the policy.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/ism/v1alpha1.Policy
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
 	PolicyID *string `json:"policyId,omitempty" tf:"policy_id,omitempty"`
 
+	// Reference to a Policy in ism to populate policyId.
+	// +kubebuilder:validation:Optional
+	PolicyIDRef *v1.NamespacedReference `json:"policyIdRef,omitempty" tf:"-"`
+
+	// Selector for a Policy in ism to populate policyId.
+	// +kubebuilder:validation:Optional
+	PolicyIDSelector *v1.NamespacedSelector `json:"policyIdSelector,omitempty" tf:"-"`
+
 	// (String) After a change in policy takes place, specify the state for the index to transition to
 	// After a change in policy takes place, specify the state for the index to transition to
 	State *string `json:"state,omitempty" tf:"state,omitempty"`
@@ -88,9 +98,19 @@ type PolicyMappingParameters struct {
 
 	// (String) The name of the policy.
 	// The name of the policy.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/ism/v1alpha1.Policy
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
 	// +kubebuilder:validation:Optional
 	PolicyID *string `json:"policyId,omitempty" tf:"policy_id,omitempty"`
 
+	// Reference to a Policy in ism to populate policyId.
+	// +kubebuilder:validation:Optional
+	PolicyIDRef *v1.NamespacedReference `json:"policyIdRef,omitempty" tf:"-"`
+
+	// Selector for a Policy in ism to populate policyId.
+	// +kubebuilder:validation:Optional
+	PolicyIDSelector *v1.NamespacedSelector `json:"policyIdSelector,omitempty" tf:"-"`
+
 	// (String) After a change in policy takes place, specify the state for the index to transition to
 	// After a change in policy takes place, specify the state for the index to transition to
 	// +kubebuilder:validation:Optional
@@ -134,7 +154,6 @@ type PolicyMapping struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.indexes) || (has(self.initProvider) && has(self.initProvider.indexes))",message="spec.forProvider.indexes is a required parameter"
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.policyId) || (has(self.initProvider) && has(self.initProvider.policyId))",message="spec.forProvider.policyId is a required parameter"
 	Spec   PolicyMappingSpec   `json:"spec"`
 	Status PolicyMappingStatus `json:"status,omitempty"`
 }
@@ -0,0 +1,90 @@
+// Code generated by angryjet. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	reference "github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	errors "github.com/pkg/errors"
+	v1alpha1 "github.com/tagesjump/provider-opensearch/apis/namespaced/ingest/v1alpha1"
+	common "github.com/tagesjump/provider-opensearch/config/common"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveReferences of this Index.
+func (mg *Index) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var rsp reference.NamespacedResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.DefaultPipeline),
+		Extract:      common.ExternalNameIfReady(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.DefaultPipelineRef,
+		Selector:     mg.Spec.ForProvider.DefaultPipelineSelector,
+		To: reference.To{
+			List:    &v1alpha1.PipelineList{},
+			Managed: &v1alpha1.Pipeline{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.DefaultPipeline")
+	}
+	mg.Spec.ForProvider.DefaultPipeline = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.DefaultPipelineRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.FinalPipeline),
+		Extract:      common.ExternalNameIfReady(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.FinalPipelineRef,
+		Selector:     mg.Spec.ForProvider.FinalPipelineSelector,
+		To: reference.To{
+			List:    &v1alpha1.PipelineList{},
+			Managed: &v1alpha1.Pipeline{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.FinalPipeline")
+	}
+	mg.Spec.ForProvider.FinalPipeline = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.FinalPipelineRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.InitProvider.DefaultPipeline),
+		Extract:      common.ExternalNameIfReady(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.InitProvider.DefaultPipelineRef,
+		Selector:     mg.Spec.InitProvider.DefaultPipelineSelector,
+		To: reference.To{
+			List:    &v1alpha1.PipelineList{},
+			Managed: &v1alpha1.Pipeline{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.InitProvider.DefaultPipeline")
+	}
+	mg.Spec.InitProvider.DefaultPipeline = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.InitProvider.DefaultPipelineRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.InitProvider.FinalPipeline),
+		Extract:      common.ExternalNameIfReady(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.InitProvider.FinalPipelineRef,
+		Selector:     mg.Spec.InitProvider.FinalPipelineSelector,
+		To: reference.To{
+			List:    &v1alpha1.PipelineList{},
+			Managed: &v1alpha1.Pipeline{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.InitProvider.FinalPipeline")
+	}
+	mg.Spec.InitProvider.FinalPipeline = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.InitProvider.FinalPipelineRef = rsp.ResolvedReference
+
+	return nil
+}
@@ -70,8 +70,31 @@ type IndexInitParameters struct {
 
 	// (String) The default ingest node pipeline for this index. Index requests will fail if the default pipeline is set and the pipeline does not exist.
 	// The default ingest node pipeline for this index. Index requests will fail if the default pipeline is set and the pipeline does not exist.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/ingest/v1alpha1.Pipeline
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
 	DefaultPipeline *string `json:"defaultPipeline,omitempty" tf:"default_pipeline,omitempty"`
 
+	// Reference to a Pipeline in ingest to populate defaultPipeline.
+	// +kubebuilder:validation:Optional
+	DefaultPipelineRef *v1.NamespacedReference `json:"defaultPipelineRef,omitempty" tf:"-"`
+
+	// Selector for a Pipeline in ingest to populate defaultPipeline.
+	// +kubebuilder:validation:Optional
+	DefaultPipelineSelector *v1.NamespacedSelector `json:"defaultPipelineSelector,omitempty" tf:"-"`
+
+	// The final ingest node pipeline for this index. It runs after the default pipeline or the pipeline of the request. Index requests will fail if the final pipeline is set and the pipeline does not exist.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/ingest/v1alpha1.Pipeline
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
+	FinalPipeline *string `json:"finalPipeline,omitempty" tf:"final_pipeline,omitempty"`
+
+	// Reference to a Pipeline in ingest to populate finalPipeline.
+	// +kubebuilder:validation:Optional
+	FinalPipelineRef *v1.NamespacedReference `json:"finalPipelineRef,omitempty" tf:"-"`
+
+	// Selector for a Pipeline in ingest to populate finalPipeline.
+	// +kubebuilder:validation:Optional
+	FinalPipelineSelector *v1.NamespacedSelector `json:"finalPipelineSelector,omitempty" tf:"-"`
+
 	// (Boolean) A boolean that indicates that the index should be deleted even if it contains documents.
 	// A boolean that indicates that the index should be deleted even if it contains documents.
 	ForceDestroy *bool `json:"forceDestroy,omitempty" tf:"force_destroy,omitempty"`
@@ -172,10 +195,6 @@ type IndexInitParameters struct {
 	// The maximum number of terms that can be used in Terms Query. A stringified number.
 	MaxTermsCount *string `json:"maxTermsCount,omitempty" tf:"max_terms_count,omitempty"`
 
-	// (String) Name of the index to create
-	// Name of the index to create
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
-
 	// (String) Number of shard replicas. A stringified number.
 	// Number of shard replicas. A stringified number.
 	NumberOfReplicas *string `json:"numberOfReplicas,omitempty" tf:"number_of_replicas,omitempty"`
@@ -322,6 +341,9 @@ type IndexObservation struct {
 	// The default ingest node pipeline for this index. Index requests will fail if the default pipeline is set and the pipeline does not exist.
 	DefaultPipeline *string `json:"defaultPipeline,omitempty" tf:"default_pipeline,omitempty"`
 
+	// The final ingest node pipeline for this index. It runs after the default pipeline or the pipeline of the request. Index requests will fail if the final pipeline is set and the pipeline does not exist.
+	FinalPipeline *string `json:"finalPipeline,omitempty" tf:"final_pipeline,omitempty"`
+
 	// (Boolean) A boolean that indicates that the index should be deleted even if it contains documents.
 	// A boolean that indicates that the index should be deleted even if it contains documents.
 	ForceDestroy *bool `json:"forceDestroy,omitempty" tf:"force_destroy,omitempty"`
@@ -425,10 +447,6 @@ type IndexObservation struct {
 	// The maximum number of terms that can be used in Terms Query. A stringified number.
 	MaxTermsCount *string `json:"maxTermsCount,omitempty" tf:"max_terms_count,omitempty"`
 
-	// (String) Name of the index to create
-	// Name of the index to create
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
-
 	// (String) Number of shard replicas. A stringified number.
 	// Number of shard replicas. A stringified number.
 	NumberOfReplicas *string `json:"numberOfReplicas,omitempty" tf:"number_of_replicas,omitempty"`
@@ -587,9 +605,33 @@ type IndexParameters struct {
 
 	// (String) The default ingest node pipeline for this index. Index requests will fail if the default pipeline is set and the pipeline does not exist.
 	// The default ingest node pipeline for this index. Index requests will fail if the default pipeline is set and the pipeline does not exist.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/ingest/v1alpha1.Pipeline
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
 	// +kubebuilder:validation:Optional
 	DefaultPipeline *string `json:"defaultPipeline,omitempty" tf:"default_pipeline,omitempty"`
 
+	// Reference to a Pipeline in ingest to populate defaultPipeline.
+	// +kubebuilder:validation:Optional
+	DefaultPipelineRef *v1.NamespacedReference `json:"defaultPipelineRef,omitempty" tf:"-"`
+
+	// Selector for a Pipeline in ingest to populate defaultPipeline.
+	// +kubebuilder:validation:Optional
+	DefaultPipelineSelector *v1.NamespacedSelector `json:"defaultPipelineSelector,omitempty" tf:"-"`
+
+	// The final ingest node pipeline for this index. It runs after the default pipeline or the pipeline of the request. Index requests will fail if the final pipeline is set and the pipeline does not exist.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/ingest/v1alpha1.Pipeline
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
+	// +kubebuilder:validation:Optional
+	FinalPipeline *string `json:"finalPipeline,omitempty" tf:"final_pipeline,omitempty"`
+
+	// Reference to a Pipeline in ingest to populate finalPipeline.
+	// +kubebuilder:validation:Optional
+	FinalPipelineRef *v1.NamespacedReference `json:"finalPipelineRef,omitempty" tf:"-"`
+
+	// Selector for a Pipeline in ingest to populate finalPipeline.
+	// +kubebuilder:validation:Optional
+	FinalPipelineSelector *v1.NamespacedSelector `json:"finalPipelineSelector,omitempty" tf:"-"`
+
 	// (Boolean) A boolean that indicates that the index should be deleted even if it contains documents.
 	// A boolean that indicates that the index should be deleted even if it contains documents.
 	// +kubebuilder:validation:Optional
@@ -715,11 +757,6 @@ type IndexParameters struct {
 	// +kubebuilder:validation:Optional
 	MaxTermsCount *string `json:"maxTermsCount,omitempty" tf:"max_terms_count,omitempty"`
 
-	// (String) Name of the index to create
-	// Name of the index to create
-	// +kubebuilder:validation:Optional
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
-
 	// (String) Number of shard replicas. A stringified number.
 	// Number of shard replicas. A stringified number.
 	// +kubebuilder:validation:Optional
@@ -861,9 +898,8 @@ type IndexStatus struct {
 type Index struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name) || (has(self.initProvider) && has(self.initProvider.name))",message="spec.forProvider.name is a required parameter"
-	Spec   IndexSpec   `json:"spec"`
-	Status IndexStatus `json:"status,omitempty"`
+	Spec              IndexSpec   `json:"spec"`
+	Status            IndexStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -16,14 +16,23 @@ type PolicyInitParameters struct {
 	// The policy document.
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
 
-	// (String) The name of the SM policy.
-	// The name of the SM policy.
-	PolicyName *string `json:"policyName,omitempty" tf:"policy_name,omitempty"`
-
 	// (Number) The primary term of the SM policy version.
 	// The primary term of the SM policy version.
 	PrimaryTerm *float64 `json:"primaryTerm,omitempty" tf:"primary_term,omitempty"`
 
+	// The snapshot repository of the policy. It is set as snapshot_config.repository of the body.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/snapshot/v1alpha1.Repository
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
+	Repository *string `json:"repository,omitempty" tf:"repository,omitempty"`
+
+	// Reference to a Repository in snapshot to populate repository.
+	// +kubebuilder:validation:Optional
+	RepositoryRef *v1.NamespacedReference `json:"repositoryRef,omitempty" tf:"-"`
+
+	// Selector for a Repository in snapshot to populate repository.
+	// +kubebuilder:validation:Optional
+	RepositorySelector *v1.NamespacedSelector `json:"repositorySelector,omitempty" tf:"-"`
+
 	// (Number) The sequence number of the SM policy version.
 	// The sequence number of the SM policy version.
 	SeqNo *float64 `json:"seqNo,omitempty" tf:"seq_no,omitempty"`
@@ -38,14 +47,13 @@ type PolicyObservation struct {
 	// (String) The ID of this resource.
 	ID *string `json:"id,omitempty" tf:"id,omitempty"`
 
-	// (String) The name of the SM policy.
-	// The name of the SM policy.
-	PolicyName *string `json:"policyName,omitempty" tf:"policy_name,omitempty"`
-
 	// (Number) The primary term of the SM policy version.
 	// The primary term of the SM policy version.
 	PrimaryTerm *float64 `json:"primaryTerm,omitempty" tf:"primary_term,omitempty"`
 
+	// The snapshot repository of the policy. It is set as snapshot_config.repository of the body.
+	Repository *string `json:"repository,omitempty" tf:"repository,omitempty"`
+
 	// (Number) The sequence number of the SM policy version.
 	// The sequence number of the SM policy version.
 	SeqNo *float64 `json:"seqNo,omitempty" tf:"seq_no,omitempty"`
@@ -58,16 +66,25 @@ type PolicyParameters struct {
 	// +kubebuilder:validation:Optional
 	Body *string `json:"body,omitempty" tf:"body,omitempty"`
 
-	// (String) The name of the SM policy.
-	// The name of the SM policy.
-	// +kubebuilder:validation:Optional
-	PolicyName *string `json:"policyName,omitempty" tf:"policy_name,omitempty"`
-
 	// (Number) The primary term of the SM policy version.
 	// The primary term of the SM policy version.
 	// +kubebuilder:validation:Optional
 	PrimaryTerm *float64 `json:"primaryTerm,omitempty" tf:"primary_term,omitempty"`
 
+	// The snapshot repository of the policy. It is set as snapshot_config.repository of the body.
+	// +crossplane:generate:reference:type=github.com/tagesjump/provider-opensearch/apis/namespaced/snapshot/v1alpha1.Repository
+	// +crossplane:generate:reference:extractor=github.com/tagesjump/provider-opensearch/config/common.ExternalNameIfReady()
+	// +kubebuilder:validation:Optional
+	Repository *string `json:"repository,omitempty" tf:"repository,omitempty"`
+
+	// Reference to a Repository in snapshot to populate repository.
+	// +kubebuilder:validation:Optional
+	RepositoryRef *v1.NamespacedReference `json:"repositoryRef,omitempty" tf:"-"`
+
+	// Selector for a Repository in snapshot to populate repository.
+	// +kubebuilder:validation:Optional
+	RepositorySelector *v1.NamespacedSelector `json:"repositorySelector,omitempty" tf:"-"`
+
 	// (Number) The sequence number of the SM policy version.
 	// The sequence number of the SM policy version.
 	// +kubebuilder:validation:Optional
@@ -111,7 +128,6 @@ type Policy struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.body) || (has(self.initProvider) && has(self.initProvider.body))",message="spec.forProvider.body is a required parameter"
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.policyName) || (has(self.initProvider) && has(self.initProvider.policyName))",message="spec.forProvider.policyName is a required parameter"
 	Spec   PolicySpec   `json:"spec"`
 	Status PolicyStatus `json:"status,omitempty"`
 }
@@ -12,10 +12,6 @@ import (
 
 type RepositoryInitParameters struct {
 
-	// (String) The name of the repository.
-	// The name of the repository.
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
-
 	// (Map of String) The settings map applicable for the backend, see official documentation for plugins.
 	// The settings map applicable for the backend, see official documentation for plugins.
 	// +mapType=granular
@@ -31,10 +27,6 @@ type RepositoryObservation struct {
 	// (String) The ID of this resource.
 	ID *string `json:"id,omitempty" tf:"id,omitempty"`
 
-	// (String) The name of the repository.
-	// The name of the repository.
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
-
 	// (Map of String) The settings map applicable for the backend, see official documentation for plugins.
 	// The settings map applicable for the backend, see official documentation for plugins.
 	// +mapType=granular
@@ -47,11 +39,6 @@ type RepositoryObservation struct {
 
 type RepositoryParameters struct {
 
-	// (String) The name of the repository.
-	// The name of the repository.
-	// +kubebuilder:validation:Optional
-	Name *string `json:"name,omitempty" tf:"name,omitempty"`
-
 	// (Map of String) The settings map applicable for the backend, see official documentation for plugins.
 	// The settings map applicable for the backend, see official documentation for plugins.
 	// +kubebuilder:validation:Optional
@@ -100,7 +87,6 @@ type RepositoryStatus struct {
 type Repository struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name) || (has(self.initProvider) && has(self.initProvider.name))",message="spec.forProvider.name is a required parameter"
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.type) || (has(self.initProvider) && has(self.initProvider.type))",message="spec.forProvider.type is a required parameter"
 	Spec   RepositorySpec   `json:"spec"`
 	Status RepositoryStatus `json:"status,omitempty"`
@@ -0,0 +1,41 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+)
+
+// TypeConnected indicates whether the provider could connect to OpenSearch
+// with a ProviderConfig.
+const TypeConnected xpv1.ConditionType = "Connected"
+
+// Reasons a ProviderConfig is or is not connected.
+const (
+	ReasonProbeSucceeded xpv1.ConditionReason = "ProbeSucceeded"
+	ReasonProbeFailed    xpv1.ConditionReason = "ProbeFailed"
+)
+
+// Connected returns a condition that indicates the provider could connect
+// to OpenSearch with a ProviderConfig.
+func Connected() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConnected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonProbeSucceeded,
+	}
+}
+
+// ConnectionFailed returns a condition that indicates the provider could not
+// connect to OpenSearch with a ProviderConfig.
+func ConnectionFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConnected,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonProbeFailed,
+		Message:            err.Error(),
+	}
+}
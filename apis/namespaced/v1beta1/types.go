@@ -8,9 +8,81 @@ import (
 )
 
 // A ProviderConfigSpec defines the desired state of a ProviderConfig.
+//
+// Connection settings can be configured through the typed fields of the spec
+// or, as before, as a JSON document in the credentials. A typed field always
+// takes precedence over the corresponding setting of the credentials, and
+// the credentials provide every setting that no typed field configures. If
+// auth is set, none of the authentication settings of the credentials are
+// used.
+//
+// With the InjectedIdentity credentials source, requests are signed with the
+// AWS credentials of the provider pod, e.g. an IAM role for its
+// ServiceAccount. The url, and the region if the url is not an AWS endpoint,
+// are then taken from the spec.
+// +kubebuilder:validation:XValidation:rule="has(self.url) || has(self.endpoints) || self.credentials.source != 'None'",message="url or endpoints is required unless the credentials provide the url"
+// +kubebuilder:validation:XValidation:rule="self.credentials.source != 'InjectedIdentity' || has(self.url) || has(self.endpoints)",message="url or endpoints is required if the credentials source is InjectedIdentity"
+// +kubebuilder:validation:XValidation:rule="!(has(self.url) && has(self.endpoints))",message="url and endpoints are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="self.credentials.source != 'InjectedIdentity' || !has(self.auth) || self.auth.type == 'AWS'",message="auth type must be AWS if the credentials source is InjectedIdentity"
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
+	// +optional
+	// +kubebuilder:default={"source":"None"}
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// URL of the OpenSearch cluster.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL *string `json:"url,omitempty"`
+
+	// Endpoints of the OpenSearch cluster, as an alternative to url for
+	// clusters that are reachable through several nodes.
+	// +optional
+	Endpoints *EndpointsConfig `json:"endpoints,omitempty"`
+
+	// Auth configures how the provider authenticates to OpenSearch.
+	// +optional
+	Auth *AuthConfig `json:"auth,omitempty"`
+
+	// TLS configures the certificate material used to verify the OpenSearch
+	// server and to authenticate the provider with a client certificate.
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// Proxy is the URL of the proxy requests to OpenSearch are sent through.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(https?|socks5)://`
+	Proxy *string `json:"proxy,omitempty"`
+
+	// HostOverride sets the Host header of requests and the server name used
+	// for certificate validation, e.g. when OpenSearch is reached through a
+	// tunnel.
+	// +optional
+	HostOverride *string `json:"hostOverride,omitempty"`
+
+	// Sniff enables node sniffing. Sniffing only works if the nodes of the
+	// cluster are routable from the provider.
+	// +optional
+	Sniff *bool `json:"sniff,omitempty"`
+
+	// Healthcheck enables the health checks of the OpenSearch client, which
+	// are designed for direct access to the cluster.
+	// +optional
+	Healthcheck *bool `json:"healthcheck,omitempty"`
+
+	// OpenSearchVersion of the cluster. If set, the provider does not ping
+	// the cluster to determine its version.
+	// +optional
+	OpenSearchVersion *string `json:"opensearchVersion,omitempty"`
+
+	// Timeouts for requests to OpenSearch.
+	// +optional
+	Timeouts *TimeoutsConfig `json:"timeouts,omitempty"`
+
+	// Limits of the operations the provider runs against the cluster. Only
+	// supported by the no-fork Terraform runner.
+	// +optional
+	Limits *LimitsConfig `json:"limits,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -22,9 +94,365 @@ type ProviderCredentials struct {
 	xpv1.CommonCredentialSelectors `json:",inline"`
 }
 
+// AuthType is the method the provider authenticates to OpenSearch with.
+type AuthType string
+
+// Supported authentication methods.
+const (
+	// AuthTypeBasic authenticates with a username and password.
+	AuthTypeBasic AuthType = "Basic"
+	// AuthTypeToken sends a token in the Authorization header.
+	AuthTypeToken AuthType = "Token"
+	// AuthTypeAWS signs requests with AWS Signature Version 4.
+	AuthTypeAWS AuthType = "AWS"
+	// AuthTypeMTLS authenticates with the client certificate of the TLS
+	// configuration only.
+	AuthTypeMTLS AuthType = "MTLS"
+	// AuthTypeOIDC sends a bearer token obtained with the OAuth 2.0 client
+	// credentials flow.
+	AuthTypeOIDC AuthType = "OIDC"
+	// AuthTypeServiceAccountToken sends a short-lived token of a Kubernetes
+	// ServiceAccount as bearer token.
+	AuthTypeServiceAccountToken AuthType = "ServiceAccountToken"
+)
+
+// AuthConfig configures how the provider authenticates to OpenSearch.
+// +kubebuilder:validation:XValidation:rule="self.type != 'Basic' || has(self.basic)",message="basic is required if type is Basic"
+// +kubebuilder:validation:XValidation:rule="self.type != 'Token' || has(self.token)",message="token is required if type is Token"
+// +kubebuilder:validation:XValidation:rule="self.type != 'OIDC' || has(self.oidc)",message="oidc is required if type is OIDC"
+// +kubebuilder:validation:XValidation:rule="self.type != 'ServiceAccountToken' || has(self.serviceAccountToken)",message="serviceAccountToken is required if type is ServiceAccountToken"
+type AuthConfig struct {
+	// Type of the authentication.
+	// +kubebuilder:validation:Enum=Basic;Token;AWS;MTLS;OIDC;ServiceAccountToken
+	Type AuthType `json:"type"`
+
+	// Basic authentication settings.
+	// +optional
+	Basic *BasicAuth `json:"basic,omitempty"`
+
+	// Token authentication settings.
+	// +optional
+	Token *TokenAuth `json:"token,omitempty"`
+
+	// AWS request signing settings.
+	// +optional
+	AWS *AWSAuth `json:"aws,omitempty"`
+
+	// OIDC client credentials settings.
+	// +optional
+	OIDC *OIDCAuth `json:"oidc,omitempty"`
+
+	// ServiceAccountToken settings.
+	// +optional
+	ServiceAccountToken *ServiceAccountTokenAuth `json:"serviceAccountToken,omitempty"`
+}
+
+// OIDCAuth obtains an access token from an OpenID Connect provider with the
+// OAuth 2.0 client credentials flow. The token is cached, renewed before it
+// expires and sent as a bearer token, e.g. to the openid authentication
+// backend of the security plugin.
+type OIDCAuth struct {
+	// TokenURL is the token endpoint of the OpenID Connect provider.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	TokenURL string `json:"tokenUrl"`
+
+	// ClientID of the provider at the OpenID Connect provider.
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientId"`
+
+	// ClientSecretSecretRef references the client secret.
+	ClientSecretSecretRef xpv1.SecretKeySelector `json:"clientSecretSecretRef"`
+
+	// Scopes to request.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Audience to request, for OpenID Connect providers that support the
+	// audience parameter.
+	// +optional
+	Audience *string `json:"audience,omitempty"`
+}
+
+// BasicAuth authenticates with a username and password.
+type BasicAuth struct {
+	// Username to authenticate as.
+	// +kubebuilder:validation:MinLength=1
+	Username string `json:"username"`
+
+	// PasswordSecretRef references the password of the user.
+	PasswordSecretRef xpv1.SecretKeySelector `json:"passwordSecretRef"`
+}
+
+// TokenAuth sends a token in the Authorization header.
+type TokenAuth struct {
+	// Scheme of the Authorization header.
+	// +optional
+	// +kubebuilder:validation:Enum=ApiKey;Bearer
+	// +kubebuilder:default=ApiKey
+	Scheme *string `json:"scheme,omitempty"`
+
+	// TokenSecretRef references the token.
+	TokenSecretRef xpv1.SecretKeySelector `json:"tokenSecretRef"`
+}
+
+// AWSAuth signs requests to Amazon OpenSearch Service. Without static keys
+// the default AWS credential chain of the provider is used.
+// +kubebuilder:validation:XValidation:rule="has(self.accessKeyIdSecretRef) == has(self.secretAccessKeySecretRef)",message="accessKeyIdSecretRef and secretAccessKeySecretRef must be set together"
+type AWSAuth struct {
+	// Region of the domain. Required if the URL is not an AWS endpoint,
+	// e.g. a custom domain name.
+	// +optional
+	Region *string `json:"region,omitempty"`
+
+	// SignatureService is the service name used in the credential scope of
+	// signed requests.
+	// +optional
+	// +kubebuilder:validation:Enum=es;aoss
+	SignatureService *string `json:"signatureService,omitempty"`
+
+	// Profile of the shared AWS configuration to use.
+	// +optional
+	Profile *string `json:"profile,omitempty"`
+
+	// AssumeRoleARN is the ARN of an IAM role to assume before signing
+	// requests.
+	// +optional
+	AssumeRoleARN *string `json:"assumeRoleArn,omitempty"`
+
+	// AssumeRoleExternalID is the external ID required by the trust policy
+	// of the assumed role.
+	// +optional
+	AssumeRoleExternalID *string `json:"assumeRoleExternalId,omitempty"`
+
+	// AccessKeyIDSecretRef references a static access key ID.
+	// +optional
+	AccessKeyIDSecretRef *xpv1.SecretKeySelector `json:"accessKeyIdSecretRef,omitempty"`
+
+	// SecretAccessKeySecretRef references the secret of the static access
+	// key.
+	// +optional
+	SecretAccessKeySecretRef *xpv1.SecretKeySelector `json:"secretAccessKeySecretRef,omitempty"`
+
+	// SessionTokenSecretRef references the session token of temporary
+	// static credentials.
+	// +optional
+	SessionTokenSecretRef *xpv1.SecretKeySelector `json:"sessionTokenSecretRef,omitempty"`
+}
+
+// ServiceAccountTokenAuth requests short-lived tokens of a Kubernetes
+// ServiceAccount with the TokenRequest API, e.g. for a JWT authentication
+// domain of the security plugin that trusts the issuer of the Kubernetes
+// cluster. Tokens are renewed before they expire. The provider needs to be
+// allowed to create serviceaccounts/token for the ServiceAccount.
+type ServiceAccountTokenAuth struct {
+	// ServiceAccountRef references the ServiceAccount to request tokens
+	// for. The ServiceAccount of a namespaced ProviderConfig is always
+	// looked up in the namespace of the ProviderConfig.
+	ServiceAccountRef ServiceAccountReference `json:"serviceAccountRef"`
+
+	// Audience of the requested tokens.
+	// +kubebuilder:validation:MinLength=1
+	Audience string `json:"audience"`
+
+	// ExpirationSeconds is the requested lifetime of the tokens.
+	// +optional
+	// +kubebuilder:validation:Minimum=600
+	// +kubebuilder:default=3600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+}
+
+// A ServiceAccountReference references a Kubernetes ServiceAccount.
+type ServiceAccountReference struct {
+	// Name of the ServiceAccount.
+	Name string `json:"name"`
+
+	// Namespace of the ServiceAccount.
+	Namespace string `json:"namespace"`
+}
+
+// TLSConfig references PEM encoded certificate material stored in Secrets.
+// The material is written to a private directory of the provider and takes
+// precedence over the cacert_file, client_cert_path and client_key_path
+// settings of the credentials.
+// +kubebuilder:validation:XValidation:rule="has(self.clientCertSecretRef) == has(self.clientKeySecretRef)",message="clientCertSecretRef and clientKeySecretRef must be set together"
+type TLSConfig struct {
+	// InsecureSkipVerify disables the verification of the certificate
+	// presented by OpenSearch.
+	// +optional
+	InsecureSkipVerify *bool `json:"insecureSkipVerify,omitempty"`
+
+	// CACertSecretRef references the CA bundle used to verify the
+	// certificate presented by OpenSearch.
+	// +optional
+	CACertSecretRef *xpv1.SecretKeySelector `json:"caCertSecretRef,omitempty"`
+
+	// ClientCertSecretRef references the X509 client certificate the
+	// provider presents to OpenSearch.
+	// +optional
+	ClientCertSecretRef *xpv1.SecretKeySelector `json:"clientCertSecretRef,omitempty"`
+
+	// ClientKeySecretRef references the private key of the client
+	// certificate.
+	// +optional
+	ClientKeySecretRef *xpv1.SecretKeySelector `json:"clientKeySecretRef,omitempty"`
+}
+
+// EndpointSelection is how the provider selects an endpoint.
+type EndpointSelection string
+
+// Supported endpoint selections.
+const (
+	// EndpointSelectionFailover uses the first healthy endpoint.
+	EndpointSelectionFailover EndpointSelection = "Failover"
+	// EndpointSelectionRoundRobin uses the healthy endpoints in turn.
+	EndpointSelectionRoundRobin EndpointSelection = "RoundRobin"
+)
+
+// EndpointsConfig configures several endpoints of an OpenSearch cluster.
+// The provider checks the health of each endpoint and does not use
+// endpoints that failed their last health check or request, unless all of
+// them did. Endpoints that failed are used again once they pass a health
+// check.
+type EndpointsConfig struct {
+	// URLs of the endpoints.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Pattern=`^https?://`
+	URLs []string `json:"urls"`
+
+	// Selection of the endpoint used for an operation.
+	// +optional
+	// +kubebuilder:validation:Enum=Failover;RoundRobin
+	// +kubebuilder:default=Failover
+	Selection *EndpointSelection `json:"selection,omitempty"`
+
+	// HealthCheckInterval is how often the health of each endpoint is
+	// checked.
+	// +optional
+	// +kubebuilder:default="1m"
+	HealthCheckInterval *metav1.Duration `json:"healthCheckInterval,omitempty"`
+}
+
+// TimeoutsConfig configures timeouts for requests to OpenSearch.
+type TimeoutsConfig struct {
+	// VersionPing is how long the provider waits for the cluster to report
+	// its version when it connects. The value is rounded to seconds.
+	// +optional
+	VersionPing *metav1.Duration `json:"versionPing,omitempty"`
+}
+
+// LimitsConfig limits the operations the provider runs against an
+// OpenSearch cluster, e.g. observing or updating a managed resource. The
+// limits apply to the managed resources of all kinds that use the
+// ProviderConfig. An operation sends one or more requests to OpenSearch.
+// Limits are only supported by the no-fork Terraform runner. Managed
+// resources reconciled with the Terraform CLI fail to connect if their
+// ProviderConfig sets limits.
+type LimitsConfig struct {
+	// MaxConcurrentOperations is the maximum number of operations that run
+	// concurrently. Further operations wait until a running one finished.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentOperations *int32 `json:"maxConcurrentOperations,omitempty"`
+
+	// OperationsPerSecond is the maximum rate at which operations start.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	OperationsPerSecond *int32 `json:"operationsPerSecond,omitempty"`
+
+	// Burst is the number of operations that may start at once, in excess
+	// of OperationsPerSecond. Defaults to OperationsPerSecond.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+}
+
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// Cluster the ProviderConfig connects to, as observed by the last
+	// successful connectivity probe.
+	// +optional
+	Cluster *ClusterStatus `json:"cluster,omitempty"`
+
+	// Endpoints and their health, if the ProviderConfig configures several
+	// endpoints.
+	// +optional
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
+
+	// Versions of the provider and of the Terraform components that last
+	// reconciled the ProviderConfig.
+	// +optional
+	Versions *VersionStatus `json:"versions,omitempty"`
+}
+
+// VersionStatus describes the versions of the provider and of the
+// Terraform components it runs with.
+type VersionStatus struct {
+	// Provider is the version of the provider.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// Terraform is the version of the Terraform CLI.
+	// +optional
+	Terraform string `json:"terraform,omitempty"`
+
+	// TerraformProvider is the version of the Terraform provider.
+	// +optional
+	TerraformProvider string `json:"terraformProvider,omitempty"`
+}
+
+// EndpointStatus is the health of an endpoint.
+type EndpointStatus struct {
+	// URL of the endpoint.
+	URL string `json:"url"`
+
+	// Healthy is whether the last health check of or request to the
+	// endpoint succeeded.
+	Healthy bool `json:"healthy"`
+
+	// Message describes why the last health check of or request to the
+	// endpoint failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastSuccessTime is when a health check of or request to the endpoint
+	// last succeeded.
+	// +optional
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+}
+
+// ClusterStatus describes an OpenSearch cluster and the identity the
+// provider has in it.
+type ClusterStatus struct {
+	// Distribution of the cluster, e.g. opensearch.
+	// +optional
+	Distribution string `json:"distribution,omitempty"`
+
+	// Version of the cluster.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Name of the cluster.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// AuthenticatedUser is the user the provider is authenticated as. It
+	// is only reported by clusters that run the security plugin.
+	// +optional
+	AuthenticatedUser string `json:"authenticatedUser,omitempty"`
+
+	// BackendRoles of the authenticated user.
+	// +optional
+	BackendRoles []string `json:"backendRoles,omitempty"`
+
+	// Endpoint that served the last successful probe.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// LastProbeTime is the time of the last successful probe.
+	// +optional
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -32,6 +460,9 @@ type ProviderConfigStatus struct {
 // A ProviderConfig configures a OpenSearch provider.
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="CONNECTED",type="string",JSONPath=".status.conditions[?(@.type=='Connected')].status"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.cluster.version"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.url",priority=1
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
 // +kubebuilder:resource:scope=Namespaced
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,provider,opensearch}
@@ -81,6 +512,8 @@ type ProviderConfigUsageList struct {
 // A ClusterProviderConfig configures a OpenSearch provider.
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="CONNECTED",type="string",JSONPath=".status.conditions[?(@.type=='Connected')].status"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.cluster.version"
 // +kubebuilder:printcolumn:name="SOURCE",type="string",JSONPath=".spec.source",priority=1
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,opensearch}
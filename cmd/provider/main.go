@@ -4,35 +4,53 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	changelogsv1alpha1 "github.com/crossplane/crossplane-runtime/v2/apis/changelogs/proto/v1alpha1"
 	xpcontroller "github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/gate"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/customresourcesgate"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
 	tjcontroller "github.com/crossplane/upjet/v2/pkg/controller"
 	"github.com/crossplane/upjet/v2/pkg/terraform"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"gopkg.in/alecthomas/kingpin.v2"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	apisCluster "github.com/tagesjump/provider-opensearch/apis/cluster"
 	apisNamespaced "github.com/tagesjump/provider-opensearch/apis/namespaced"
 	"github.com/tagesjump/provider-opensearch/config"
+	"github.com/tagesjump/provider-opensearch/internal/audit"
+	"github.com/tagesjump/provider-opensearch/internal/changelogs"
 	"github.com/tagesjump/provider-opensearch/internal/clients"
+	"github.com/tagesjump/provider-opensearch/internal/controller/cli"
 	controllerCluster "github.com/tagesjump/provider-opensearch/internal/controller/cluster"
+	providerconfigCluster "github.com/tagesjump/provider-opensearch/internal/controller/cluster/providerconfig"
 	controllerNamespaced "github.com/tagesjump/provider-opensearch/internal/controller/namespaced"
+	providerconfigNamespaced "github.com/tagesjump/provider-opensearch/internal/controller/namespaced/providerconfig"
+	"github.com/tagesjump/provider-opensearch/internal/controller/selection"
 	"github.com/tagesjump/provider-opensearch/internal/features"
+	"github.com/tagesjump/provider-opensearch/internal/health"
+	"github.com/tagesjump/provider-opensearch/internal/logs"
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+	"github.com/tagesjump/provider-opensearch/internal/version"
 )
 
 const (
@@ -40,12 +58,19 @@ const (
 	tlsServerCertDirEnvVar  = "TLS_SERVER_CERTS_DIR"
 	certsDirEnvVar          = "CERTS_DIR"
 	tlsServerCertDir        = "/tls/server"
+
+	// Terraform runners.
+	runnerNoFork     = "no-fork"
+	runnerSharedGRPC = "shared-grpc"
+	runnerCLI        = "cli"
 )
 
 func main() {
 	var (
-		app   = kingpin.New(filepath.Base(os.Args[0]), "Terraform based Crossplane provider for OpenSearch").DefaultEnvars()
-		debug = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		app       = kingpin.New(filepath.Base(os.Args[0]), "Terraform based Crossplane provider for OpenSearch").DefaultEnvars()
+		debug     = app.Flag("debug", "Run with debug logging. Equivalent to --log-level=debug.").Short('d').Bool()
+		logLevel  = app.Flag("log-level", "The minimum level of the logs.").Default(logs.LevelInfo).Envar("LOG_LEVEL").Enum(logs.LevelDebug, logs.LevelInfo, logs.LevelError)
+		logFormat = app.Flag("log-format", "The format of the logs.").Default(logs.FormatJSON).Envar("LOG_FORMAT").Enum(logs.FormatJSON, logs.FormatConsole)
 		// syncPeriod       = app.Flag("cache-sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
 		syncInterval = app.Flag("sync", "Sync interval controls how often all resources will be double checked for drift.").Short('s').Default("1h").Duration()
 
@@ -57,7 +82,26 @@ func main() {
 		providerSource   = app.Flag("terraform-provider-source", "Terraform provider source.").Required().Envar("TERRAFORM_PROVIDER_SOURCE").String()
 		providerVersion  = app.Flag("terraform-provider-version", "Terraform provider version.").Required().Envar("TERRAFORM_PROVIDER_VERSION").String()
 
+		runner             = app.Flag("terraform-runner", "How managed resources are reconciled: with the Terraform provider built into the provider (no-fork), with the Terraform CLI and a native provider process shared over gRPC (shared-grpc), or with the Terraform CLI forking the native provider (cli). Attributes the provider adds to Terraform resources, e.g. finalPipeline of indices, and the limits of ProviderConfigs are only supported by no-fork. The metrics of Terraform operations and drift, and the liveness check of stalled Terraform operations, are only recorded by no-fork; the other runners only track stalled reconciles.").Default(runnerNoFork).Envar("TERRAFORM_RUNNER").Enum(runnerNoFork, runnerSharedGRPC, runnerCLI)
+		nativeProviderPath = app.Flag("terraform-native-provider-path", "Terraform native provider path for the shared-grpc runner.").Envar("TERRAFORM_NATIVE_PROVIDER_PATH").String()
+		pluginProcessTTL   = app.Flag("provider-ttl", "TTL for the native provider processes of the shared-grpc runner before they are replaced. Changing the default is not recommended.").Default("100").Int()
+
+		watchNamespaces   = app.Flag("watch-namespaces", "Comma-separated namespaces whose namespaced managed resources, ProviderConfigs and Secrets are watched. All namespaces are watched if none are supplied. Cluster-scoped kinds are always watched.").Envar("WATCH_NAMESPACES").String()
+		secretNamespaces  = app.Flag("secret-namespaces", "Comma-separated namespaces of Secrets that cluster-scoped ProviderConfigs and managed resources reference, which are watched in addition to the namespaces of --watch-namespaces and the namespace of the provider.").Envar("SECRET_NAMESPACES").String()
+		providerNamespace = app.Flag("provider-namespace", "Namespace the provider runs in. Its Secrets are watched even if it is not one of --watch-namespaces.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
+
+		enableGroups = app.Flag("enable-groups", "Comma-separated groups of the managed resources whose controllers run, e.g. index,ism. The controllers of all groups run if none are supplied.").Envar("ENABLE_GROUPS").String()
+		disableKinds = app.Flag("disable-kinds", "Comma-separated kinds of the managed resources whose controllers do not run, optionally qualified with their group, e.g. Role.security.").Envar("DISABLE_KINDS").String()
+
+		healthProbeBindAddress = app.Flag("health-probe-bind-address", "The address the liveness and readiness probe endpoints bind to.").Default(":8081").Envar("HEALTH_PROBE_BIND_ADDRESS").String()
+		livenessWindow         = app.Flag("liveness-window", "How long a Terraform operation or reconcile may run without completing before the provider is reported as not alive.").Default("30m").Envar("LIVENESS_WINDOW").Duration()
+
 		enableManagementPolicies = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("true").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
+		enableChangeLogs         = app.Flag("enable-changelogs", "Enable support for capturing change logs during reconciliation.").Default("false").Envar("ENABLE_CHANGE_LOGS").Bool()
+		auditProviderConfig      = app.Flag("audit-provider-config", "ProviderConfig of the OpenSearch cluster a record of every change is written to, e.g. ClusterProviderConfig/audit, ProviderConfig/audit or ProviderConfig/<namespace>/audit. No records are written if it is not set.").Envar("AUDIT_PROVIDER_CONFIG").String()
+		auditIndex               = app.Flag("audit-index", "Index the records of changes are written to.").Default("provider-opensearch-audit").Envar("AUDIT_INDEX").String()
+		auditFlushInterval       = app.Flag("audit-flush-interval", "How often records of changes are written.").Default("5s").Envar("AUDIT_FLUSH_INTERVAL").Duration()
+		changelogsSocketPath     = app.Flag("changelogs-socket-path", "Path of the change logs socket, if change logs are enabled.").Default("/var/run/changelogs/changelogs.sock").Envar("CHANGELOGS_SOCKET_PATH").String()
 
 		certsDirSet = false
 		// we record whether the command-line option "--certs-dir" was supplied
@@ -70,17 +114,33 @@ func main() {
 
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
-	zl := zap.New(zap.UseDevMode(*debug))
-	log := logging.NewLogrLogger(zl.WithName("provider-opensearch"))
 	if *debug {
+		*logLevel = logs.LevelDebug
+	}
+	zl, err := logs.New(*logLevel, *logFormat)
+	kingpin.FatalIfError(err, "Cannot create logger")
+	log := logging.NewLogrLogger(logs.WithReconcileKeys(zl).WithName("provider-opensearch"))
+	if *logLevel == logs.LevelDebug {
 		// The controller-runtime runs with a no-op logger by default. It is
 		// *very* verbose even at info level, so we only provide it a real
 		// logger when we're running in debug mode.
 		ctrl.SetLogger(zl)
 	}
 
+	version.SetTerraform(*terraformVersion, *providerVersion)
+	v := version.Get()
+	log.Info("Starting", "version", v.Version, "terraform-version", v.TerraformVersion, "terraform-provider-version", v.TerraformProviderVersion)
+	metrics.BuildInfo.WithLabelValues(v.Version, v.TerraformVersion, v.TerraformProviderVersion).Set(1)
 	log.Debug("Starting", "sync-interval", syncInterval.String(), "poll-interval", pollInterval.String(), "max-reconcile-rate", *maxReconcileRate)
 
+	var scheduler terraform.ProviderScheduler
+	if *runner == runnerSharedGRPC {
+		kingpin.FatalIfError(checkNativeProviderPath(*nativeProviderPath), "Cannot use the shared-grpc runner")
+		scheduler = terraform.NewSharedProviderScheduler(log, *pluginProcessTTL,
+			terraform.WithSharedProviderOptions(terraform.WithNativeProviderPath(*nativeProviderPath), terraform.WithNativeProviderName("registry.terraform.io/"+*providerSource)))
+	}
+	log.Info("Reconciling managed resources", "terraform-runner", *runner, "terraform-native-provider-path", *nativeProviderPath)
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
@@ -106,12 +166,16 @@ func main() {
 		}
 	}
 
+	namespaces := splitList(*watchNamespaces)
+	if len(namespaces) > 0 {
+		log.Info("Watching namespaces", "namespaces", namespaces)
+	}
+
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
-		LeaderElection:   *leaderElection,
-		LeaderElectionID: "crossplane-leader-election-provider-opensearch",
-		Cache: cache.Options{
-			SyncPeriod: syncInterval,
-		},
+		LeaderElection:             *leaderElection,
+		LeaderElectionID:           "crossplane-leader-election-provider-opensearch",
+		Cache:                      cacheOptions(syncInterval, namespaces, append(splitList(*secretNamespaces), *providerNamespace)),
+		HealthProbeBindAddress:     *healthProbeBindAddress,
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
@@ -121,69 +185,234 @@ func main() {
 	kingpin.FatalIfError(apisNamespaced.AddToScheme(mgr.GetScheme()), "Cannot add namespaced OpenSearch APIs to scheme")
 	kingpin.FatalIfError(apiextensionsv1.AddToScheme(mgr.GetScheme()), "Cannot add api-extensions APIs to scheme")
 	kingpin.FatalIfError(authv1.AddToScheme(mgr.GetScheme()), "Cannot add k8s authorization APIs to scheme")
+	kingpin.FatalIfError(mgr.AddHealthzCheck("ping", healthz.Ping), "Cannot add ping liveness check")
+	kingpin.FatalIfError(mgr.AddHealthzCheck("progress", health.InProgress.StallChecker(*livenessWindow)), "Cannot add progress liveness check")
+	kingpin.FatalIfError(mgr.AddReadyzCheck("cache-sync", health.CacheSyncChecker(mgr.GetCache())), "Cannot add cache sync readiness check")
+	kingpin.FatalIfError(mgr.AddReadyzCheck("leader", health.LeaderChecker(mgr.Elected())), "Cannot add leader readiness check")
+
+	// The limiter is shared by the controllers of both scopes, so that
+	// they do not exceed the maximum reconcile rate together.
+	globalRateLimiter := ratelimiter.NewGlobal(*maxReconcileRate)
+
+	var changeLoggers []managed.ChangeLogger
+	if *enableChangeLogs {
+		conn, err := grpc.NewClient("unix://"+*changelogsSocketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		kingpin.FatalIfError(err, "Cannot create change logs client connection to %s", *changelogsSocketPath)
+		changeLoggers = append(changeLoggers, managed.NewGRPCChangeLogger(
+			changelogsv1alpha1.NewChangeLogServiceClient(conn),
+			managed.WithProviderVersion("provider-opensearch:"+version.Version)))
+	}
+	if *auditProviderConfig != "" {
+		pc, err := audit.ParseProviderConfig(*auditProviderConfig)
+		kingpin.FatalIfError(err, "Cannot parse audit ProviderConfig reference")
+		w := audit.NewWriter(mgr.GetClient(), pc, *auditIndex, audit.WithLogger(zl.WithName("provider-opensearch").WithValues("controller", "audit")), audit.WithFlushInterval(*auditFlushInterval))
+		kingpin.FatalIfError(mgr.Add(w), "Cannot add audit record writer")
+		changeLoggers = append(changeLoggers, audit.NewChangeLogger(mgr.GetScheme(), w))
+		log.Info("Writing audit records", "provider-config", *auditProviderConfig, "index", *auditIndex)
+	}
+	// Upjet runs the operations of the no-fork runner asynchronously, after
+	// the managed reconciler recorded their change log entries. Their
+	// entries are recorded by the operations once they completed instead.
+	var opsOpts []clients.OperationsOption
+	if len(changeLoggers) > 0 && *runner == runnerNoFork {
+		opsOpts = append(opsOpts, clients.WithChangeLogger(changelogs.Tee(changeLoggers...)), clients.WithLogger(log.WithValues("controller", "changelogs")))
+	}
+
+	var setupOpts []clients.SetupOption
+	if *runner != runnerNoFork {
+		setupOpts = append(setupOpts, clients.WithTerraformCLI())
+	}
 
 	provider, err := config.GetProvider(false)
+	kingpin.FatalIfError(err, "Cannot get cluster-scoped Terraform provider configuration")
+	clients.WrapOperations(provider, opsOpts...)
+	if *runner != runnerNoFork {
+		clients.RejectExtensions(provider)
+	}
 	clusterOpts := tjcontroller.Options{
 		Options: xpcontroller.Options{
 			Logger:                  log,
-			GlobalRateLimiter:       ratelimiter.NewGlobal(*maxReconcileRate),
+			GlobalRateLimiter:       globalRateLimiter,
 			PollInterval:            *pollInterval,
 			MaxConcurrentReconciles: *maxReconcileRate,
 			Features:                &feature.Flags{},
 		},
-		Provider: provider,
-		// use the following WorkspaceStoreOption to enable the shared gRPC mode
-		// terraform.WithProviderRunner(terraform.NewSharedProvider(log, os.Getenv("TERRAFORM_NATIVE_PROVIDER_PATH"), terraform.WithNativeProviderArgs("-debuggable")))
-		WorkspaceStore: terraform.NewWorkspaceStore(log),
-		SetupFn:        clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion),
-		StartWebhooks:  *certsDir != "",
+		Provider:              provider,
+		WorkspaceStore:        terraform.NewWorkspaceStore(log),
+		OperationTrackerStore: tjcontroller.NewOperationStore(log),
+		SetupFn:               clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion, provider.TerraformProvider, scheduler, setupOpts...),
+		StartWebhooks:         *certsDir != "",
 	}
 
 	providerNamespaced, err := config.GetProviderNamespaced(false)
+	kingpin.FatalIfError(err, "Cannot get namespaced Terraform provider configuration")
+	clients.WrapOperations(providerNamespaced, opsOpts...)
+	if *runner != runnerNoFork {
+		clients.RejectExtensions(providerNamespaced)
+	}
 	namespacedOpts := tjcontroller.Options{
 		Options: xpcontroller.Options{
 			Logger:                  log,
-			GlobalRateLimiter:       ratelimiter.NewGlobal(*maxReconcileRate),
+			GlobalRateLimiter:       globalRateLimiter,
 			PollInterval:            *pollInterval,
 			MaxConcurrentReconciles: *maxReconcileRate,
 			Features:                &feature.Flags{},
 		},
-		Provider: providerNamespaced,
-		// use the following WorkspaceStoreOption to enable the shared gRPC mode
-		// terraform.WithProviderRunner(terraform.NewSharedProvider(log, os.Getenv("TERRAFORM_NATIVE_PROVIDER_PATH"), terraform.WithNativeProviderArgs("-debuggable")))
-		WorkspaceStore: terraform.NewWorkspaceStore(log),
-		SetupFn:        clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion),
-		StartWebhooks:  *certsDir != "",
+		Provider:              providerNamespaced,
+		WorkspaceStore:        terraform.NewWorkspaceStore(log),
+		OperationTrackerStore: tjcontroller.NewOperationStore(log),
+		SetupFn:               clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion, providerNamespaced.TerraformProvider, scheduler, setupOpts...),
+		StartWebhooks:         *certsDir != "",
 	}
 
+	for _, p := range []*ujconfig.Provider{provider, providerNamespaced} {
+		for _, r := range p.Resources {
+			logs.RegisterKind(selection.GroupVersionKind(p, r))
+		}
+	}
+
+	sel, err := selection.New(splitList(*enableGroups), splitList(*disableKinds), provider, providerNamespaced)
+	kingpin.FatalIfError(err, "Cannot select managed resource controllers")
+	log.Info("Selected managed resource controllers", "kinds", sel.Kinds())
+
 	if *enableManagementPolicies {
 		clusterOpts.Features.Enable(features.EnableBetaManagementPolicies)
 		namespacedOpts.Features.Enable(features.EnableBetaManagementPolicies)
 		log.Info("Beta feature enabled", "flag", features.EnableBetaManagementPolicies)
 	}
 
+	if len(changeLoggers) > 0 && *runner != runnerNoFork {
+		// The managed reconcilers of the CLI runners record the change log
+		// entries, which audit records are written with, too.
+		clusterOpts.Features.Enable(feature.EnableAlphaChangeLogs)
+		namespacedOpts.Features.Enable(feature.EnableAlphaChangeLogs)
+		log.Info("Alpha feature enabled", "flag", feature.EnableAlphaChangeLogs)
+
+		clo := &xpcontroller.ChangeLogOptions{
+			ChangeLogger: changelogs.Tee(changeLoggers...),
+		}
+		clusterOpts.ChangeLogOptions = clo
+		namespacedOpts.ChangeLogOptions = clo
+	}
+
 	canSafeStart, err := canWatchCRD(context.TODO(), mgr)
 	kingpin.FatalIfError(err, "SafeStart precheck failed")
+	// The controllers register with a gate that sets up only the
+	// controllers of the selected kinds. Without SafeStart, they are set up
+	// immediately instead of once their CRDs are available.
+	var g xpcontroller.Gate = selection.ImmediateGate{}
 	if canSafeStart {
-		crdGate := new(gate.Gate[schema.GroupVersionKind])
-		clusterOpts.Gate = crdGate
-		namespacedOpts.Gate = crdGate
+		crdGate := health.NewGate(new(gate.Gate[schema.GroupVersionKind]))
+		g = crdGate
+		kingpin.FatalIfError(mgr.AddReadyzCheck("crd-gate", crdGate.Check), "Cannot add CRD gate readiness check")
 		kingpin.FatalIfError(customresourcesgate.Setup(mgr, xpcontroller.Options{
 			Logger:                  log,
 			Gate:                    crdGate,
 			MaxConcurrentReconciles: 1,
 		}), "Cannot setup CRD gate")
-		kingpin.FatalIfError(controllerCluster.SetupGated(mgr, clusterOpts), "Cannot setup cluster-scoped Template controllers")
-		kingpin.FatalIfError(controllerNamespaced.SetupGated(mgr, namespacedOpts), "Cannot setup namespaced Template controllers")
 	} else {
 		log.Info("Provider has missing RBAC permissions for watching CRDs, controller SafeStart capability will be disabled")
-		kingpin.FatalIfError(controllerCluster.Setup(mgr, clusterOpts), "Cannot setup cluster-scoped Template controllers")
-		kingpin.FatalIfError(controllerNamespaced.Setup(mgr, namespacedOpts), "Cannot setup namespaced Template controllers")
+	}
+	clusterOpts.Gate = sel.Gate(g)
+	namespacedOpts.Gate = sel.Gate(g)
+	setupCluster, setupNamespaced := controllerSetups(*runner)
+	// The gated setup functions only log the errors of setting up
+	// controllers, with the logger of the manager, which is a no-op unless
+	// debugging. Errors are only recorded until the setup functions
+	// returned, so that the errors controllers log while reconciling are
+	// not.
+	smgr := selection.NewSetupManager(mgr, zl.WithName("provider-opensearch"))
+	kingpin.FatalIfError(setupCluster(smgr, clusterOpts), "Cannot setup cluster-scoped Template controllers")
+	kingpin.FatalIfError(setupNamespaced(smgr, namespacedOpts), "Cannot setup namespaced Template controllers")
+	setupErr := smgr.Err()
+	if !canSafeStart {
+		// The immediate gate set up all controllers already.
+		kingpin.FatalIfError(setupErr, "Cannot setup controllers")
 	}
 
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
 
+// A setupFn adds controllers to a manager.
+type setupFn func(ctrl.Manager, tjcontroller.Options) error
+
+// controllerSetups returns the functions that set up the cluster-scoped and
+// namespaced controllers for the supplied Terraform runner. The generated
+// managed resource controllers reconcile with the no-fork runner, so the
+// other runners set up the ProviderConfig controllers and CLI-based managed
+// resource controllers instead.
+func controllerSetups(runner string) (cluster, namespaced setupFn) {
+	if runner == runnerNoFork {
+		return controllerCluster.SetupGated, controllerNamespaced.SetupGated
+	}
+	return all(providerconfigCluster.SetupGated, cli.SetupGated), all(providerconfigNamespaced.SetupGated, cli.SetupGated)
+}
+
+// all returns a setupFn that runs the supplied setupFns in order.
+func all(fns ...setupFn) setupFn {
+	return func(mgr ctrl.Manager, o tjcontroller.Options) error {
+		for _, fn := range fns {
+			if err := fn(mgr, o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// cacheOptions returns the options of the cache of the controller manager.
+// If namespaces are supplied, only namespaced objects in them are cached.
+// Cluster-scoped ProviderConfigs and managed resources may reference Secrets
+// in namespaces that are not watched, so Secrets are also cached in the
+// supplied Secret namespaces. Cluster-scoped objects are always cached.
+func cacheOptions(syncPeriod *time.Duration, namespaces, secretNamespaces []string) cache.Options {
+	o := cache.Options{SyncPeriod: syncPeriod}
+	if len(namespaces) == 0 {
+		return o
+	}
+	o.DefaultNamespaces = make(map[string]cache.Config, len(namespaces))
+	secrets := make(map[string]cache.Config, len(namespaces)+len(secretNamespaces))
+	for _, ns := range namespaces {
+		o.DefaultNamespaces[ns] = cache.Config{}
+		secrets[ns] = cache.Config{}
+	}
+	for _, ns := range secretNamespaces {
+		if ns != "" {
+			secrets[ns] = cache.Config{}
+		}
+	}
+	o.ByObject = map[client.Object]cache.ByObject{&corev1.Secret{}: {Namespaces: secrets}}
+	return o
+}
+
+// splitList returns the non-empty elements of the supplied comma-separated
+// list.
+func splitList(s string) []string {
+	var l []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			l = append(l, e)
+		}
+	}
+	return l
+}
+
+// checkNativeProviderPath returns an error unless the supplied path is an
+// executable file.
+func checkNativeProviderPath(path string) error {
+	if path == "" {
+		return errors.New("the native provider path is not set")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrap(err, "cannot stat the native provider")
+	}
+	if !fi.Mode().IsRegular() || fi.Mode().Perm()&0o111 == 0 {
+		return errors.Errorf("native provider %s is not an executable file", path)
+	}
+	return nil
+}
+
 func canWatchCRD(ctx context.Context, mgr manager.Manager) (bool, error) {
 	if err := authv1.AddToScheme(mgr.GetScheme()); err != nil {
 		return false, err
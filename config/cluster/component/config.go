@@ -0,0 +1,8 @@
+package component
+
+const (
+	// ApisPackagePath is the golang path for this package.
+	ApisPackagePath = "github.com/tagesjump/provider-opensearch/apis/cluster/component/v1alpha1"
+	// ConfigPath is the golang path for this package.
+	ConfigPath = "github.com/tagesjump/provider-opensearch/config/cluster/component"
+)
@@ -0,0 +1,26 @@
+package ism
+
+import (
+	"fmt"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/tagesjump/provider-opensearch/config/common"
+)
+
+const (
+	// ApisPackagePath is the golang path for this package.
+	ApisPackagePath = "github.com/tagesjump/provider-opensearch/apis/cluster/ism/v1alpha1"
+	// ConfigPath is the golang path for this package.
+	ConfigPath = "github.com/tagesjump/provider-opensearch/config/cluster/ism"
+)
+
+// Configure adds configurations for the ism group.
+func Configure(p *config.Provider) {
+	p.AddResourceConfigurator("opensearch_ism_policy_mapping", func(r *config.Resource) {
+		// The mapping waits until the policy exists.
+		r.References["policy_id"] = config.Reference{
+			Type:      fmt.Sprintf("%s.%s", ApisPackagePath, "Policy"),
+			Extractor: common.ExternalNameIfReadyExtractor,
+		}
+	})
+}
@@ -0,0 +1,33 @@
+// Package common contains configuration shared by the resources of the
+// cluster and namespaced APIs.
+package common
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SelfPackagePath is the golang path for this package.
+	SelfPackagePath = "github.com/tagesjump/provider-opensearch/config/common"
+
+	// ExternalNameIfReadyExtractor is the extractor of references that
+	// resolve to the external name of ready managed resources.
+	ExternalNameIfReadyExtractor = SelfPackagePath + ".ExternalNameIfReady()"
+)
+
+// ExternalNameIfReady returns an extractor that returns the external name of
+// a managed resource once it is ready, i.e. once its object exists. Until
+// then it returns an empty value, so that resolving a reference to it fails
+// and the referencing managed resource waits for it.
+func ExternalNameIfReady() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		if mg.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+			return ""
+		}
+		return meta.GetExternalName(mg)
+	}
+}
@@ -0,0 +1,122 @@
+package common
+
+import (
+	"encoding/json"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseJSON  = "cannot parse %s as JSON"
+	errEncodeJSON = "cannot encode %s as JSON"
+	errNotObject  = "%s of %s is not a JSON object"
+	errNotArray   = "%s of %s is not a JSON array"
+)
+
+type jsonFieldConversion struct {
+	param     string
+	jsonParam string
+	path      []string
+	// set returns the value of the field given the value of param and the
+	// current value of the field, which is nil if it is not set.
+	set func(v, current any) (any, error)
+}
+
+// NewJSONFieldConversion returns a Terraform conversion that sets the field
+// at the supplied path of the JSON document in jsonParam to the value of
+// param before it is passed to Terraform, e.g. to inject the value of a
+// resolved reference into a document. Documents are left as they are if
+// param is not set. The Terraform provider does not know param, which is
+// thus removed from the parameters.
+func NewJSONFieldConversion(param, jsonParam string, path ...string) config.TerraformConversion {
+	return &jsonFieldConversion{param: param, jsonParam: jsonParam, path: path, set: func(v, _ any) (any, error) {
+		return v, nil
+	}}
+}
+
+// NewJSONListConversion returns a Terraform conversion that adds the values
+// of the list param to the array at the supplied path of the JSON document in
+// jsonParam before it is passed to Terraform. Values the array already has,
+// and empty values, are not added. Documents are left as they are if param is empty.
+// Like with NewJSONFieldConversion, param is removed from the parameters.
+func NewJSONListConversion(param, jsonParam string, path ...string) config.TerraformConversion {
+	c := &jsonFieldConversion{param: param, jsonParam: jsonParam, path: path}
+	c.set = func(v, current any) (any, error) {
+		l := []any{}
+		if current != nil {
+			var ok bool
+			if l, ok = current.([]any); !ok {
+				return nil, errors.Errorf(errNotArray, path[len(path)-1], jsonParam)
+			}
+		}
+		for _, e := range v.([]any) {
+			if e != nil && e != "" && !contains(l, e) {
+				l = append(l, e)
+			}
+		}
+		return l, nil
+	}
+	return c
+}
+
+func contains(l []any, v any) bool {
+	for _, e := range l {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *jsonFieldConversion) Convert(params map[string]any, _ *config.Resource, mode config.Mode) (map[string]any, error) {
+	if mode != config.ToTerraform {
+		return params, nil
+	}
+	// Neither the Terraform CLI nor the Terraform provider know param.
+	pv := params[c.param]
+	delete(params, c.param)
+	switch v := pv.(type) {
+	case string:
+		if v == "" {
+			return params, nil
+		}
+	case []any:
+		if len(v) == 0 {
+			return params, nil
+		}
+	default:
+		return params, nil
+	}
+	doc, ok := params[c.jsonParam].(string)
+	if !ok || doc == "" {
+		return params, nil
+	}
+	root := map[string]any{}
+	if err := json.Unmarshal([]byte(doc), &root); err != nil {
+		return nil, errors.Wrapf(err, errParseJSON, c.jsonParam)
+	}
+	obj := root
+	for _, k := range c.path[:len(c.path)-1] {
+		if _, ok := obj[k]; !ok {
+			obj[k] = map[string]any{}
+		}
+		next, ok := obj[k].(map[string]any)
+		if !ok {
+			return nil, errors.Errorf(errNotObject, k, c.jsonParam)
+		}
+		obj = next
+	}
+	k := c.path[len(c.path)-1]
+	v, err := c.set(pv, obj[k])
+	if err != nil {
+		return nil, err
+	}
+	obj[k] = v
+	b, err := json.Marshal(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, errEncodeJSON, c.jsonParam)
+	}
+	params[c.jsonParam] = string(b)
+	return params, nil
+}
@@ -0,0 +1,84 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+)
+
+func TestJSONFieldConversion(t *testing.T) {
+	cases := map[string]struct {
+		c       config.TerraformConversion
+		params  map[string]any
+		mode    config.Mode
+		want    map[string]any
+		wantErr bool
+	}{
+		"FieldSet": {
+			c:      NewJSONFieldConversion("repository", "body", "snapshot_config", "repository"),
+			params: map[string]any{"repository": "backups", "body": `{"snapshot_config":{"indices":"*"}}`},
+			mode:   config.ToTerraform,
+			want:   map[string]any{"body": `{"snapshot_config":{"indices":"*","repository":"backups"}}`},
+		},
+		"FieldCreatesObjects": {
+			c:      NewJSONFieldConversion("repository", "body", "snapshot_config", "repository"),
+			params: map[string]any{"repository": "backups", "body": `{}`},
+			mode:   config.ToTerraform,
+			want:   map[string]any{"body": `{"snapshot_config":{"repository":"backups"}}`},
+		},
+		"FieldNotSet": {
+			c:      NewJSONFieldConversion("repository", "body", "snapshot_config", "repository"),
+			params: map[string]any{"repository": "", "body": `{"snapshot_config":{"repository":"a"}}`},
+			mode:   config.ToTerraform,
+			want:   map[string]any{"body": `{"snapshot_config":{"repository":"a"}}`},
+		},
+		"FieldNotAnObject": {
+			c:       NewJSONFieldConversion("repository", "body", "snapshot_config", "repository"),
+			params:  map[string]any{"repository": "backups", "body": `{"snapshot_config":"a"}`},
+			mode:    config.ToTerraform,
+			wantErr: true,
+		},
+		"FieldInvalidJSON": {
+			c:       NewJSONFieldConversion("repository", "body", "snapshot_config", "repository"),
+			params:  map[string]any{"repository": "backups", "body": `{`},
+			mode:    config.ToTerraform,
+			wantErr: true,
+		},
+		"FromTerraform": {
+			c:      NewJSONFieldConversion("repository", "body", "snapshot_config", "repository"),
+			params: map[string]any{"repository": "backups", "body": `{}`},
+			mode:   config.FromTerraform,
+			want:   map[string]any{"repository": "backups", "body": `{}`},
+		},
+		"ListAppended": {
+			c:      NewJSONListConversion("composed_of", "body", "composed_of"),
+			params: map[string]any{"composed_of": []any{"a", "b", ""}, "body": `{"composed_of":["b"]}`},
+			mode:   config.ToTerraform,
+			want:   map[string]any{"body": `{"composed_of":["b","a"]}`},
+		},
+		"ListEmpty": {
+			c:      NewJSONListConversion("composed_of", "body", "composed_of"),
+			params: map[string]any{"composed_of": []any{}, "body": `{"composed_of":["b"]}`},
+			mode:   config.ToTerraform,
+			want:   map[string]any{"body": `{"composed_of":["b"]}`},
+		},
+		"ListNotAnArray": {
+			c:       NewJSONListConversion("composed_of", "body", "composed_of"),
+			params:  map[string]any{"composed_of": []any{"a"}, "body": `{"composed_of":"b"}`},
+			mode:    config.ToTerraform,
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.c.Convert(tc.params, nil, tc.mode)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Convert(...): error = %v, want error %t", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("Convert(...) = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,35 +1,50 @@
 package config
 
 import (
+	"context"
+	"strings"
+
 	"github.com/crossplane/upjet/v2/pkg/config"
 )
 
+// smPolicyIDSuffix is appended to the name of a snapshot management policy
+// to form its ID.
+const smPolicyIDSuffix = "-sm-policy"
+
 // TerraformPluginSDKExternalNameConfigs contains all external name configurations
 // belonging to Terraform Plugin SDKv2 resources to be reconciled
 // under the no-fork architecture for this provider.
 var TerraformPluginSDKExternalNameConfigs = map[string]config.ExternalName{
 	// Import requires using a randomly generated ID from provider: nl-2e21sda
-	"opensearch_anomaly_detection":         config.IdentifierFromProvider,
-	"opensearch_audit_config":              config.IdentifierFromProvider,
-	"opensearch_channel_configuration":     config.IdentifierFromProvider,
-	"opensearch_cluster_settings":          config.IdentifierFromProvider,
-	"opensearch_component_template":        config.IdentifierFromProvider,
-	"opensearch_composable_index_template": config.IdentifierFromProvider,
-	"opensearch_dashboard_object":          config.IdentifierFromProvider,
-	"opensearch_dashboard_tenant":          config.IdentifierFromProvider,
-	"opensearch_data_stream":               config.IdentifierFromProvider,
-	"opensearch_index":                     config.IdentifierFromProvider,
-	"opensearch_index_template":            config.IdentifierFromProvider,
-	"opensearch_ingest_pipeline":           config.IdentifierFromProvider,
-	"opensearch_ism_policy":                config.IdentifierFromProvider,
-	"opensearch_ism_policy_mapping":        config.IdentifierFromProvider,
-	"opensearch_monitor":                   config.IdentifierFromProvider,
-	"opensearch_role":                      config.IdentifierFromProvider,
-	"opensearch_roles_mapping":             config.IdentifierFromProvider,
-	"opensearch_script":                    config.IdentifierFromProvider,
-	"opensearch_sm_policy":                 config.IdentifierFromProvider,
-	"opensearch_snapshot_repository":       config.IdentifierFromProvider,
-	"opensearch_user":                      config.IdentifierFromProvider,
+	"opensearch_anomaly_detection":     config.IdentifierFromProvider,
+	"opensearch_channel_configuration": config.IdentifierFromProvider,
+	"opensearch_dashboard_object":      config.IdentifierFromProvider,
+	"opensearch_monitor":               config.IdentifierFromProvider,
+
+	// Import requires the constant ID of the singleton: settings
+	"opensearch_audit_config":     config.IdentifierFromProvider,
+	"opensearch_cluster_settings": config.IdentifierFromProvider,
+
+	// Import requires using the name of the object: logs
+	"opensearch_component_template":        config.NameAsIdentifier,
+	"opensearch_composable_index_template": config.NameAsIdentifier,
+	"opensearch_data_stream":               config.NameAsIdentifier,
+	"opensearch_index":                     config.NameAsIdentifier,
+	"opensearch_index_template":            config.NameAsIdentifier,
+	"opensearch_ingest_pipeline":           config.NameAsIdentifier,
+	"opensearch_snapshot_repository":       config.NameAsIdentifier,
+	"opensearch_dashboard_tenant":          config.ParameterAsIdentifier("tenant_name"),
+	"opensearch_ism_policy":                config.ParameterAsIdentifier("policy_id"),
+	"opensearch_role":                      config.ParameterAsIdentifier("role_name"),
+	"opensearch_script":                    config.ParameterAsIdentifier("script_id"),
+	"opensearch_sm_policy":                 smPolicy(),
+	"opensearch_user":                      config.ParameterAsIdentifier("username"),
+
+	// Import requires using the name of the mapped role: logs_writer
+	"opensearch_roles_mapping": parameterAsID("role_name"),
+
+	// Import requires using the index pattern of the mapping: logs-*
+	"opensearch_ism_policy_mapping": parameterAsID("indexes"),
 }
 
 // TerraformPluginFrameworkExternalNameConfigs contains all external
@@ -57,3 +72,33 @@ func ExternalNameConfigurations() config.ResourceOption {
 		}
 	}
 }
+
+// parameterAsID uses the supplied parameter as the Terraform ID. Unlike with
+// config.ParameterAsIdentifier, the parameter stays in the spec, e.g. so that
+// it can reference another managed resource, and the external name is
+// derived from it.
+func parameterAsID(param string) config.ExternalName {
+	e := config.TemplatedStringAsIdentifier("", "{{ .parameters."+param+" }}")
+	// Identifier fields are left out of initProvider, but their reference
+	// resolvers are not.
+	e.IdentifierFields = nil
+	return e
+}
+
+// smPolicy uses the policy_name field as the external name of snapshot
+// management policies. Their Terraform ID is the policy name suffixed with
+// -sm-policy.
+func smPolicy() config.ExternalName {
+	e := config.ParameterAsIdentifier("policy_name")
+	e.GetIDFn = func(_ context.Context, externalName string, _ map[string]any, _ map[string]any) (string, error) {
+		return externalName + smPolicyIDSuffix, nil
+	}
+	e.GetExternalNameFn = func(tfstate map[string]any) (string, error) {
+		if name, ok := tfstate["policy_name"].(string); ok && name != "" {
+			return name, nil
+		}
+		id, err := config.IDAsExternalName(tfstate)
+		return strings.TrimSuffix(id, smPolicyIDSuffix), err
+	}
+	return e
+}
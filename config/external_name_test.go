@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExternalNameConfigsRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		externalName string
+		params       map[string]any
+		wantID       string
+	}{
+		"opensearch_anomaly_detection":         {externalName: "nl-2e21sda", wantID: "nl-2e21sda"},
+		"opensearch_channel_configuration":     {externalName: "nl-2e21sda", wantID: "nl-2e21sda"},
+		"opensearch_dashboard_object":          {externalName: "nl-2e21sda", wantID: "nl-2e21sda"},
+		"opensearch_monitor":                   {externalName: "nl-2e21sda", wantID: "nl-2e21sda"},
+		"opensearch_audit_config":              {externalName: "settings", wantID: "settings"},
+		"opensearch_cluster_settings":          {externalName: "settings", wantID: "settings"},
+		"opensearch_component_template":        {externalName: "logs", wantID: "logs"},
+		"opensearch_composable_index_template": {externalName: "logs", wantID: "logs"},
+		"opensearch_data_stream":               {externalName: "logs", wantID: "logs"},
+		"opensearch_index":                     {externalName: "logs", wantID: "logs"},
+		"opensearch_index_template":            {externalName: "logs", wantID: "logs"},
+		"opensearch_ingest_pipeline":           {externalName: "logs", wantID: "logs"},
+		"opensearch_snapshot_repository":       {externalName: "logs", wantID: "logs"},
+		"opensearch_dashboard_tenant":          {externalName: "logs", params: map[string]any{"tenant_name": "logs"}, wantID: "logs"},
+		"opensearch_ism_policy":                {externalName: "logs", params: map[string]any{"policy_id": "logs"}, wantID: "logs"},
+		"opensearch_role":                      {externalName: "logs", params: map[string]any{"role_name": "logs"}, wantID: "logs"},
+		"opensearch_script":                    {externalName: "logs", params: map[string]any{"script_id": "logs"}, wantID: "logs"},
+		"opensearch_sm_policy":                 {externalName: "daily", params: map[string]any{"policy_name": "daily"}, wantID: "daily-sm-policy"},
+		"opensearch_user":                      {externalName: "logs", params: map[string]any{"username": "logs"}, wantID: "logs"},
+		"opensearch_roles_mapping":             {externalName: "logs_writer", params: map[string]any{"role_name": "logs_writer"}, wantID: "logs_writer"},
+		"opensearch_ism_policy_mapping":        {externalName: "logs-*", params: map[string]any{"indexes": "logs-*"}, wantID: "logs-*"},
+	}
+	for name := range TerraformPluginSDKExternalNameConfigs {
+		if _, ok := cases[name]; !ok {
+			t.Errorf("%s: no test case", name)
+		}
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, ok := TerraformPluginSDKExternalNameConfigs[name]
+			if !ok {
+				t.Fatalf("no external name configuration")
+			}
+			params := map[string]any{}
+			for k, v := range tc.params {
+				params[k] = v
+			}
+			id, err := e.GetIDFn(context.Background(), tc.externalName, params, map[string]any{})
+			if err != nil {
+				t.Fatalf("GetIDFn(...): %v", err)
+			}
+			if id != tc.wantID {
+				t.Errorf("GetIDFn(...): want %q, got %q", tc.wantID, id)
+			}
+			// The state holds the parameters and the ID.
+			tfstate := map[string]any{"id": id}
+			for k, v := range tc.params {
+				tfstate[k] = v
+			}
+			got, err := e.GetExternalNameFn(tfstate)
+			if err != nil {
+				t.Fatalf("GetExternalNameFn(...): %v", err)
+			}
+			if got != tc.externalName {
+				t.Errorf("GetExternalNameFn(...): want %q, got %q", tc.externalName, got)
+			}
+		})
+	}
+}
+
+func TestSMPolicyExternalNameFromID(t *testing.T) {
+	// The state of an imported policy may only hold its ID.
+	got, err := smPolicy().GetExternalNameFn(map[string]any{"id": "daily-sm-policy"})
+	if err != nil {
+		t.Fatalf("GetExternalNameFn(...): %v", err)
+	}
+	if got != "daily" {
+		t.Errorf("GetExternalNameFn(...): want %q, got %q", "daily", got)
+	}
+}
@@ -0,0 +1,38 @@
+package composable
+
+import (
+	"fmt"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tagesjump/provider-opensearch/config/common"
+	"github.com/tagesjump/provider-opensearch/config/namespaced/component"
+)
+
+const (
+	// ApisPackagePath is the golang path for this package.
+	ApisPackagePath = "github.com/tagesjump/provider-opensearch/apis/namespaced/composable/v1alpha1"
+	// ConfigPath is the golang path for this package.
+	ConfigPath = "github.com/tagesjump/provider-opensearch/config/namespaced/composable"
+)
+
+// Configure adds configurations for the composable group.
+func Configure(p *config.Provider) {
+	p.AddResourceConfigurator("opensearch_composable_index_template", func(r *config.Resource) {
+		// The template names its component templates in its body, which
+		// cannot reference a Template. The composed_of field can, and is
+		// merged into the body before the template is applied.
+		r.TerraformResource.Schema["composed_of"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The component templates the template is composed of. They are appended to composed_of of the body, unless it already names them.",
+		}
+		r.References["composed_of"] = config.Reference{
+			Type:      fmt.Sprintf("%s.%s", component.ApisPackagePath, "Template"),
+			Extractor: common.ExternalNameIfReadyExtractor,
+		}
+		r.TerraformConversions = append(r.TerraformConversions,
+			common.NewJSONListConversion("composed_of", "body", "composed_of"))
+	})
+}
@@ -1,7 +1,12 @@
 package opensearch
 
 import (
+	"fmt"
+
 	"github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tagesjump/provider-opensearch/config/common"
+	"github.com/tagesjump/provider-opensearch/config/namespaced/ingest"
 )
 
 const (
@@ -12,4 +17,21 @@ const (
 )
 
 // Configure adds configurations for the opensearch group.
-func Configure(p *config.Provider) {}
+func Configure(p *config.Provider) {
+	p.AddResourceConfigurator("opensearch_index", func(r *config.Resource) {
+		// The Terraform provider does not know the final pipeline. The
+		// provider sets and reads it itself.
+		r.TerraformResource.Schema["final_pipeline"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The final ingest node pipeline for this index. It runs after the default pipeline or the pipeline of the request. Index requests will fail if the final pipeline is set and the pipeline does not exist.",
+		}
+		// Indices wait until their pipelines exist.
+		for _, field := range []string{"default_pipeline", "final_pipeline"} {
+			r.References[field] = config.Reference{
+				Type:      fmt.Sprintf("%s.%s", ingest.ApisPackagePath, "Pipeline"),
+				Extractor: common.ExternalNameIfReadyExtractor,
+			}
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package sm
+
+import (
+	"fmt"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tagesjump/provider-opensearch/config/common"
+	"github.com/tagesjump/provider-opensearch/config/namespaced/snapshot"
+)
+
+const (
+	// ApisPackagePath is the golang path for this package.
+	ApisPackagePath = "github.com/tagesjump/provider-opensearch/apis/namespaced/sm/v1alpha1"
+	// ConfigPath is the golang path for this package.
+	ConfigPath = "github.com/tagesjump/provider-opensearch/config/namespaced/sm"
+)
+
+// Configure adds configurations for the sm group.
+func Configure(p *config.Provider) {
+	p.AddResourceConfigurator("opensearch_sm_policy", func(r *config.Resource) {
+		// The policy names its repository in its body, which cannot
+		// reference a Repository. The repository field can, and is
+		// injected into the body before the policy is applied.
+		r.TerraformResource.Schema["repository"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The snapshot repository of the policy. It is set as snapshot_config.repository of the body.",
+		}
+		r.References["repository"] = config.Reference{
+			Type:      fmt.Sprintf("%s.%s", snapshot.ApisPackagePath, "Repository"),
+			Extractor: common.ExternalNameIfReadyExtractor,
+		}
+		r.TerraformConversions = append(r.TerraformConversions,
+			common.NewJSONFieldConversion("repository", "body", "snapshot_config", "repository"))
+	})
+}
@@ -13,8 +13,16 @@ import (
 	conversiontfjson "github.com/crossplane/upjet/v2/pkg/types/conversion/tfjson"
 
 	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	composableClustered "github.com/tagesjump/provider-opensearch/config/cluster/composable"
+	ismClustered "github.com/tagesjump/provider-opensearch/config/cluster/ism"
+	opensearchClustered "github.com/tagesjump/provider-opensearch/config/cluster/opensearch"
 	rolesClustered "github.com/tagesjump/provider-opensearch/config/cluster/roles"
+	smClustered "github.com/tagesjump/provider-opensearch/config/cluster/sm"
+	composableNamespaced "github.com/tagesjump/provider-opensearch/config/namespaced/composable"
+	ismNamespaced "github.com/tagesjump/provider-opensearch/config/namespaced/ism"
+	opensearchNamespaced "github.com/tagesjump/provider-opensearch/config/namespaced/opensearch"
 	namespacedClustered "github.com/tagesjump/provider-opensearch/config/namespaced/roles"
+	smNamespaced "github.com/tagesjump/provider-opensearch/config/namespaced/sm"
 )
 
 const (
@@ -73,7 +81,11 @@ func GetProvider(generationProvider bool) (*ujconfig.Provider, error) {
 		))
 
 	for _, configure := range []func(provider *ujconfig.Provider){
+		opensearchClustered.Configure,
 		rolesClustered.Configure,
+		ismClustered.Configure,
+		smClustered.Configure,
+		composableClustered.Configure,
 	} {
 		configure(pc)
 	}
@@ -106,7 +118,11 @@ func GetProviderNamespaced(generationProvider bool) (*ujconfig.Provider, error)
 		))
 
 	for _, configure := range []func(provider *ujconfig.Provider){
+		opensearchNamespaced.Configure,
 		namespacedClustered.Configure,
+		ismNamespaced.Configure,
+		smNamespaced.Configure,
+		composableNamespaced.Configure,
 	} {
 		configure(pc)
 	}
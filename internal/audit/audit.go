@@ -0,0 +1,189 @@
+// Package audit writes a record of every change the provider makes to
+// OpenSearch into an OpenSearch index.
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/apis/changelogs/proto/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	clusterv1beta1 "github.com/tagesjump/provider-opensearch/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+	"github.com/tagesjump/provider-opensearch/internal/changelogs"
+	"github.com/tagesjump/provider-opensearch/internal/version"
+)
+
+const (
+	errParseReference = "ProviderConfig reference %q is neither ProviderConfig/<name>, ClusterProviderConfig/<name> nor ProviderConfig/<namespace>/<name>"
+)
+
+// Operations of records.
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// A Record is the document written for a change the provider made to
+// OpenSearch. Updates either apply a changed desired state or correct
+// drift.
+type Record struct {
+	Timestamp time.Time `json:"@timestamp"`
+
+	// Operation is create, update or delete.
+	Operation string `json:"operation"`
+
+	// Succeeded is whether the operation succeeded.
+	Succeeded bool `json:"succeeded"`
+
+	// Error the operation failed with.
+	Error string `json:"error,omitempty"`
+
+	// Kind of the managed resource, qualified with its group.
+	Kind string `json:"kind"`
+
+	// ExternalName of the managed resource.
+	ExternalName string `json:"externalName,omitempty"`
+
+	// Object is the managed resource.
+	Object ObjectReference `json:"object"`
+
+	// ProviderConfig the managed resource uses.
+	ProviderConfig string `json:"providerConfig,omitempty"`
+
+	// ChangedAttributes are the top-level Terraform attributes the
+	// operation changed, if known.
+	ChangedAttributes []string `json:"changedAttributes,omitempty"`
+
+	// Drift is whether an update corrected drift, i.e. restored an
+	// attribute of the OpenSearch resource to the value the provider last
+	// applied. It is false if that value is not known, e.g. because the
+	// provider restarted since it applied it.
+	Drift bool `json:"drift"`
+
+	// Provider is the version of the provider.
+	Provider string `json:"provider"`
+}
+
+// An ObjectReference refers to a Kubernetes object.
+type ObjectReference struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	UID        types.UID `json:"uid"`
+}
+
+// ParseProviderConfig returns an empty ProviderConfig with the name and
+// namespace of the supplied reference. ProviderConfig/<name> refers to a
+// cluster-scoped ProviderConfig, ClusterProviderConfig/<name> and
+// ProviderConfig/<namespace>/<name> to a ProviderConfig of the namespaced
+// API.
+func ParseProviderConfig(ref string) (client.Object, error) {
+	parts := strings.Split(ref, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == clusterv1beta1.ProviderConfigKind:
+		return &clusterv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: parts[1]}}, nil
+	case len(parts) == 2 && parts[0] == namespacedv1beta1.ClusterProviderConfigKind:
+		return &namespacedv1beta1.ClusterProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: parts[1]}}, nil
+	case len(parts) == 3 && parts[0] == namespacedv1beta1.ProviderConfigKind:
+		return &namespacedv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Namespace: parts[1], Name: parts[2]}}, nil
+	}
+	return nil, errors.Errorf(errParseReference, ref)
+}
+
+// NewRecord returns the record of the supplied change log entry. The
+// external name after the change is preferred over the one of the managed
+// resource, which is only known after the create of resources whose
+// identifier OpenSearch chooses.
+func NewRecord(s *runtime.Scheme, mg resource.Managed, op v1alpha1.OperationType, changeErr error, ad managed.AdditionalDetails) Record {
+	gvk, err := apiutil.GVKForObject(mg, s)
+	if err != nil {
+		gvk = mg.GetObjectKind().GroupVersionKind()
+	}
+	r := Record{
+		Timestamp:    time.Now().UTC(),
+		Operation:    operation(op),
+		Succeeded:    changeErr == nil,
+		Kind:         gvk.GroupKind().String(),
+		ExternalName: meta.GetExternalName(mg),
+		Object: ObjectReference{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  mg.GetNamespace(),
+			Name:       mg.GetName(),
+			UID:        mg.GetUID(),
+		},
+		ProviderConfig: providerConfig(mg),
+		Provider:       version.Version,
+	}
+	if changeErr != nil {
+		r.Error = changeErr.Error()
+	}
+	if n := ad[changelogs.DetailExternalName]; n != "" {
+		r.ExternalName = n
+	}
+	if c := ad[changelogs.DetailChangedAttributes]; c != "" {
+		r.ChangedAttributes = strings.Split(c, ",")
+	}
+	r.Drift = ad[changelogs.DetailDrift] == "true"
+	return r
+}
+
+func operation(op v1alpha1.OperationType) string {
+	switch op {
+	case v1alpha1.OperationType_OPERATION_TYPE_CREATE:
+		return OperationCreate
+	case v1alpha1.OperationType_OPERATION_TYPE_UPDATE:
+		return OperationUpdate
+	case v1alpha1.OperationType_OPERATION_TYPE_DELETE:
+		return OperationDelete
+	}
+	return strings.ToLower(strings.TrimPrefix(op.String(), "OPERATION_TYPE_"))
+}
+
+// providerConfig returns the kind and name of the ProviderConfig the
+// supplied managed resource references.
+func providerConfig(mg resource.Managed) string {
+	switch mg := mg.(type) {
+	case resource.LegacyManaged:
+		if ref := mg.GetProviderConfigReference(); ref != nil {
+			return clusterv1beta1.ProviderConfigKind + "/" + ref.Name
+		}
+	case resource.ModernManaged:
+		if ref := mg.GetProviderConfigReference(); ref != nil {
+			return ref.Kind + "/" + ref.Name
+		}
+	}
+	return ""
+}
+
+// A ChangeLogger enqueues a record of every change log entry with a Writer.
+type ChangeLogger struct {
+	scheme *runtime.Scheme
+	writer *Writer
+}
+
+// NewChangeLogger returns a ChangeLogger that enqueues records with the
+// supplied Writer. The supplied scheme knows the kinds of managed resources.
+func NewChangeLogger(s *runtime.Scheme, w *Writer) *ChangeLogger {
+	return &ChangeLogger{scheme: s, writer: w}
+}
+
+// Log enqueues the record of the supplied change log entry. It never
+// blocks the reconcile.
+func (l *ChangeLogger) Log(_ context.Context, mg resource.Managed, op v1alpha1.OperationType, changeErr error, ad managed.AdditionalDetails) error {
+	l.writer.Enqueue(NewRecord(l.scheme, mg, op, changeErr, ad))
+	return nil
+}
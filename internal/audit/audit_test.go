@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/apis/changelogs/proto/v1alpha1"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apisCluster "github.com/tagesjump/provider-opensearch/apis/cluster"
+	opensearchv1alpha1 "github.com/tagesjump/provider-opensearch/apis/cluster/opensearch/v1alpha1"
+	"github.com/tagesjump/provider-opensearch/internal/changelogs"
+	"github.com/tagesjump/provider-opensearch/internal/version"
+)
+
+func TestNewRecord(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apisCluster.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme(...): %v", err)
+	}
+	monitor := func(externalName string) *opensearchv1alpha1.Monitor {
+		mg := &opensearchv1alpha1.Monitor{ObjectMeta: metav1.ObjectMeta{Name: "errors", UID: "uid"}}
+		mg.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+		if externalName != "" {
+			meta.SetExternalName(mg, externalName)
+		}
+		return mg
+	}
+	object := ObjectReference{APIVersion: "opensearch.opensearch.upbound.io/v1alpha1", Kind: "Monitor", Name: "errors", UID: "uid"}
+
+	cases := map[string]struct {
+		mg   *opensearchv1alpha1.Monitor
+		op   v1alpha1.OperationType
+		err  error
+		ad   managed.AdditionalDetails
+		want Record
+	}{
+		"CreateSucceeded": {
+			mg: monitor(""),
+			op: v1alpha1.OperationType_OPERATION_TYPE_CREATE,
+			ad: managed.AdditionalDetails{
+				changelogs.DetailChangedAttributes: "body,name",
+				changelogs.DetailExternalName:      "nl-2e21sda",
+			},
+			want: Record{
+				Operation:         OperationCreate,
+				Succeeded:         true,
+				Kind:              "Monitor.opensearch.opensearch.upbound.io",
+				ExternalName:      "nl-2e21sda",
+				Object:            object,
+				ProviderConfig:    "ProviderConfig/default",
+				ChangedAttributes: []string{"body", "name"},
+				Provider:          version.Version,
+			},
+		},
+		"UpdateFailed": {
+			mg:  monitor("nl-2e21sda"),
+			op:  v1alpha1.OperationType_OPERATION_TYPE_UPDATE,
+			err: errors.New("elastic: Error 409 (Conflict)"),
+			ad:  managed.AdditionalDetails{changelogs.DetailChangedAttributes: ""},
+			want: Record{
+				Operation:      OperationUpdate,
+				Error:          "elastic: Error 409 (Conflict)",
+				Kind:           "Monitor.opensearch.opensearch.upbound.io",
+				ExternalName:   "nl-2e21sda",
+				Object:         object,
+				ProviderConfig: "ProviderConfig/default",
+				Provider:       version.Version,
+			},
+		},
+		"DriftCorrected": {
+			mg: monitor("nl-2e21sda"),
+			op: v1alpha1.OperationType_OPERATION_TYPE_UPDATE,
+			ad: managed.AdditionalDetails{
+				changelogs.DetailChangedAttributes: "body",
+				changelogs.DetailDrift:             "true",
+			},
+			want: Record{
+				Operation:         OperationUpdate,
+				Succeeded:         true,
+				Kind:              "Monitor.opensearch.opensearch.upbound.io",
+				ExternalName:      "nl-2e21sda",
+				Object:            object,
+				ProviderConfig:    "ProviderConfig/default",
+				ChangedAttributes: []string{"body"},
+				Drift:             true,
+				Provider:          version.Version,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewRecord(s, tc.mg, tc.op, tc.err, tc.ad)
+			if time.Since(got.Timestamp) > time.Minute {
+				t.Errorf("NewRecord(...).Timestamp = %v, want now", got.Timestamp)
+			}
+			got.Timestamp = time.Time{}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("NewRecord(...) = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
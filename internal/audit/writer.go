@@ -0,0 +1,310 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tagesjump/provider-opensearch/internal/clients"
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+)
+
+const (
+	defaultFlushInterval = 5 * time.Second
+	defaultQueueSize     = 10000
+	maxBatchSize         = 500
+
+	// defaultRetryQueueSize is how many records of batches that could not
+	// be written are kept to be retried.
+	defaultRetryQueueSize = 10000
+
+	// maxRetryBackoff is the longest a Writer waits before it retries
+	// writing records. It starts with the flush interval and doubles with
+	// every failure.
+	maxRetryBackoff = 5 * time.Minute
+
+	// flushTimeout is how long the records left when the Writer stops may
+	// take to be written.
+	flushTimeout = 10 * time.Second
+
+	bulkPath = "/_bulk"
+
+	errGetProviderConfig = "cannot get audit ProviderConfig"
+	errResolve           = "cannot resolve audit ProviderConfig"
+	errConfigure         = "cannot configure audit ProviderConfig"
+	errHTTPClient        = "cannot create audit HTTP client"
+	errEncodeRecord      = "cannot encode audit record"
+	errBulkRequest       = "cannot send audit records"
+	errBulkStatus        = "cannot write audit records: %s"
+	errDecodeResponse    = "cannot decode bulk response"
+	errBulkItems         = "cannot write %d of %d audit records: %s"
+)
+
+// Results of records.
+const (
+	resultWritten = "written"
+	resultFailed  = "failed"
+	resultDropped = "dropped"
+)
+
+// A Writer writes records to an OpenSearch index in batches. It writes with
+// the OpenSearch cluster of a ProviderConfig, which it reads before every
+// batch, so that changes to the ProviderConfig are picked up. Records that
+// could not be written are retried with a backoff, oldest first, until
+// more than the retry queue size of them are waiting.
+type Writer struct {
+	kube           client.Client
+	pc             client.Object
+	index          string
+	log            logr.Logger
+	flushInterval  time.Duration
+	retryQueueSize int
+	records        chan Record
+
+	// retries holds the records that could not be written yet, oldest
+	// first. They are retried once retryAt passed.
+	retries []Record
+	backoff time.Duration
+	retryAt time.Time
+}
+
+// A WriterOption configures a Writer.
+type WriterOption func(*Writer)
+
+// WithLogger configures the logger of a Writer.
+func WithLogger(l logr.Logger) WriterOption {
+	return func(w *Writer) {
+		w.log = l
+	}
+}
+
+// WithFlushInterval configures how often a Writer writes the records it
+// has, unless it has enough for a full batch earlier.
+func WithFlushInterval(d time.Duration) WriterOption {
+	return func(w *Writer) {
+		w.flushInterval = d
+	}
+}
+
+// WithRetryQueueSize configures how many records that could not be written
+// a Writer keeps to be retried. The oldest records are dropped once more
+// are waiting.
+func WithRetryQueueSize(n int) WriterOption {
+	return func(w *Writer) {
+		w.retryQueueSize = n
+	}
+}
+
+// NewWriter returns a Writer that writes records to the supplied index of
+// the OpenSearch cluster of the supplied ProviderConfig.
+func NewWriter(kube client.Client, pc client.Object, index string, o ...WriterOption) *Writer {
+	w := &Writer{
+		kube:           kube,
+		pc:             pc,
+		index:          index,
+		log:            logr.Discard(),
+		flushInterval:  defaultFlushInterval,
+		retryQueueSize: defaultRetryQueueSize,
+		records:        make(chan Record, defaultQueueSize),
+	}
+	for _, fn := range o {
+		fn(w)
+	}
+	return w
+}
+
+// Enqueue enqueues the supplied record to be written. It never blocks; the
+// record is dropped if the queue is full.
+func (w *Writer) Enqueue(r Record) {
+	select {
+	case w.records <- r:
+	default:
+		metrics.AuditRecords.WithLabelValues(resultDropped).Inc()
+	}
+}
+
+// Start writes the enqueued records until the supplied context is done.
+func (w *Writer) Start(ctx context.Context) error {
+	t := time.NewTicker(w.flushInterval)
+	defer t.Stop()
+	batch := make([]Record, 0, maxBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			// The records left are written once more regardless of the
+			// backoff, and lost if that fails.
+			fctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+			w.retryAt = time.Time{}
+			w.flush(fctx, w.drain(batch))
+			cancel()
+			w.drop(len(w.retries), "the provider stopped")
+			return nil
+		case r := <-w.records:
+			if batch = append(batch, r); len(batch) < maxBatchSize {
+				continue
+			}
+		case <-t.C:
+		}
+		w.flush(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+// drain returns the supplied batch with all enqueued records.
+func (w *Writer) drain(batch []Record) []Record {
+	for {
+		select {
+		case r := <-w.records:
+			batch = append(batch, r)
+		default:
+			return batch
+		}
+	}
+}
+
+// flush writes the records that wait to be retried, unless their backoff
+// did not pass yet, followed by the supplied batch. Records that cannot be
+// written wait to be retried.
+func (w *Writer) flush(ctx context.Context, batch []Record) {
+	records := append(w.retries, batch...)
+	w.retries = nil
+	if len(records) == 0 {
+		return
+	}
+	if time.Now().Before(w.retryAt) {
+		w.retry(records)
+		return
+	}
+	for len(records) > 0 {
+		n := min(len(records), maxBatchSize)
+		if err := w.write(ctx, records[:n]); err != nil {
+			w.backoff = min(max(2*w.backoff, w.flushInterval), maxRetryBackoff)
+			w.retryAt = time.Now().Add(w.backoff)
+			w.log.Info("Cannot write audit records, retrying", "records", n, "waiting", len(records), "retryAfter", w.backoff, "error", err)
+			metrics.AuditRecords.WithLabelValues(resultFailed).Add(float64(n))
+			w.retry(records)
+			return
+		}
+		metrics.AuditRecords.WithLabelValues(resultWritten).Add(float64(n))
+		records = records[n:]
+	}
+	w.backoff = 0
+}
+
+// retry keeps the supplied records to be retried. The oldest records are
+// dropped if more than the retry queue size are waiting.
+func (w *Writer) retry(records []Record) {
+	w.retries = records
+	if over := len(w.retries) - w.retryQueueSize; over > 0 {
+		w.retries = w.retries[over:]
+		w.drop(over, "too many records are waiting to be retried")
+	}
+}
+
+// drop records the supplied number of records as lost.
+func (w *Writer) drop(n int, reason string) {
+	if n <= 0 {
+		return
+	}
+	w.log.Error(errors.New(reason), "Dropped audit records that could not be written", "records", n)
+	metrics.AuditRecords.WithLabelValues(resultDropped).Add(float64(n))
+}
+
+// write writes the supplied records with a bulk request.
+func (w *Writer) write(ctx context.Context, records []Record) error {
+	if err := w.kube.Get(ctx, client.ObjectKeyFromObject(w.pc), w.pc); err != nil {
+		return errors.Wrap(err, errGetProviderConfig)
+	}
+	key, spec, err := clients.ResolveProviderConfig(w.pc)
+	if err != nil {
+		return errors.Wrap(err, errResolve)
+	}
+	cfg, err := clients.Configuration(ctx, w.kube, key, spec)
+	if err != nil {
+		return errors.Wrap(err, errConfigure)
+	}
+	hc, base, err := clients.HTTPClient(key, cfg)
+	if err != nil {
+		return errors.Wrap(err, errHTTPClient)
+	}
+	body, err := w.bulkBody(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(clients.WithPathTemplate(ctx, bulkPath), http.MethodPost, strings.TrimSuffix(base, "/")+bulkPath, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errBulkRequest)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errBulkRequest)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return errors.Errorf(errBulkStatus, resp.Status)
+	}
+	return bulkError(resp.Body, len(records))
+}
+
+// bulkBody returns the body of a bulk request that indexes the supplied
+// records.
+func (w *Writer) bulkBody(records []Record) ([]byte, error) {
+	action, err := json.Marshal(map[string]any{"index": map[string]string{"_index": w.index}})
+	if err != nil {
+		return nil, errors.Wrap(err, errEncodeRecord)
+	}
+	var b bytes.Buffer
+	for _, r := range records {
+		doc, err := json.Marshal(r)
+		if err != nil {
+			return nil, errors.Wrap(err, errEncodeRecord)
+		}
+		b.Write(action)
+		b.WriteByte('\n')
+		b.Write(doc)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// bulkError returns an error if the supplied bulk response reports that
+// records could not be written.
+func bulkError(body io.Reader, total int) error {
+	br := &bulkResponse{}
+	if err := json.NewDecoder(body).Decode(br); err != nil {
+		return errors.Wrap(err, errDecodeResponse)
+	}
+	if !br.Errors {
+		return nil
+	}
+	failed, reason := 0, ""
+	for _, item := range br.Items {
+		for _, res := range item {
+			if res.Status >= http.StatusMultipleChoices {
+				failed++
+				reason = res.Error.Reason
+			}
+		}
+	}
+	return errors.Errorf(errBulkItems, failed, total, reason)
+}
@@ -0,0 +1,178 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apisCluster "github.com/tagesjump/provider-opensearch/apis/cluster"
+	clusterv1beta1 "github.com/tagesjump/provider-opensearch/apis/cluster/v1beta1"
+)
+
+// bulkServer is an OpenSearch bulk endpoint that fails while it is down.
+type bulkServer struct {
+	mu      sync.Mutex
+	down    bool
+	written []string
+}
+
+func (s *bulkServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *bulkServer) names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.written...)
+}
+
+func (s *bulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	sc := bufio.NewScanner(r.Body)
+	for line := 0; sc.Scan(); line++ {
+		if line%2 == 0 {
+			continue
+		}
+		rec := Record{}
+		if err := json.Unmarshal(sc.Bytes(), &rec); err == nil {
+			s.written = append(s.written, rec.Object.Name)
+		}
+	}
+	_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+}
+
+// errorSink records the messages of the errors logged with it.
+type errorSink struct {
+	errs *[]string
+}
+
+func (s errorSink) Init(logr.RuntimeInfo)               {}
+func (s errorSink) Enabled(int) bool                    { return true }
+func (s errorSink) Info(int, string, ...any)            {}
+func (s errorSink) WithValues(...any) logr.LogSink      { return s }
+func (s errorSink) WithName(string) logr.LogSink        { return s }
+func (s errorSink) Error(_ error, msg string, _ ...any) { *s.errs = append(*s.errs, msg) }
+
+func newTestWriter(t *testing.T, srv *bulkServer, errLog logr.Logger, o ...WriterOption) *Writer {
+	t.Helper()
+	hs := httptest.NewServer(srv)
+	t.Cleanup(hs.Close)
+	s := runtime.NewScheme()
+	if err := apisCluster.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme(...): %v", err)
+	}
+	pc := &clusterv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: t.Name()},
+		Spec: clusterv1beta1.ProviderConfigSpec{
+			Credentials: clusterv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceNone},
+			URL:         ptr.To(hs.URL),
+		},
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(pc).Build()
+	o = append([]WriterOption{WithLogger(errLog), WithFlushInterval(time.Minute)}, o...)
+	return NewWriter(kube, &clusterv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: t.Name()}}, "audit", o...)
+}
+
+func records(names ...string) []Record {
+	rs := make([]Record, 0, len(names))
+	for _, n := range names {
+		rs = append(rs, Record{Object: ObjectReference{Name: n}})
+	}
+	return rs
+}
+
+func TestWriterRetriesFailedRecords(t *testing.T) {
+	srv := &bulkServer{down: true}
+	var errs []string
+	w := newTestWriter(t, srv, logr.New(errorSink{errs: &errs}))
+	ctx := context.Background()
+
+	w.flush(ctx, records("a", "b"))
+	if len(w.retries) != 2 || w.retryAt.IsZero() {
+		t.Fatalf("retries = %d, retry at %v, want the failed records kept with a backoff", len(w.retries), w.retryAt)
+	}
+	first := w.backoff
+
+	// Records are not written while the backoff did not pass.
+	srv.setDown(false)
+	w.flush(ctx, records("c"))
+	if got := srv.names(); len(got) != 0 {
+		t.Fatalf("written = %v, want nothing before the backoff passed", got)
+	}
+
+	// The backoff grows with every failure.
+	srv.setDown(true)
+	w.retryAt = time.Time{}
+	w.flush(ctx, nil)
+	if w.backoff <= first {
+		t.Errorf("backoff = %v, want more than %v after another failure", w.backoff, first)
+	}
+
+	srv.setDown(false)
+	w.retryAt = time.Time{}
+	w.flush(ctx, records("d"))
+	if got, want := srv.names(), []string{"a", "b", "c", "d"}; len(got) != len(want) || got[0] != "a" || got[3] != "d" {
+		t.Errorf("written = %v, want %v in order", got, want)
+	}
+	if len(w.retries) != 0 || w.backoff != 0 {
+		t.Errorf("retries = %d, backoff = %v, want none once written", len(w.retries), w.backoff)
+	}
+	if len(errs) != 0 {
+		t.Errorf("logged errors %v, want none while no records were dropped", errs)
+	}
+}
+
+func TestWriterDropsOldestRecords(t *testing.T) {
+	srv := &bulkServer{down: true}
+	var errs []string
+	w := newTestWriter(t, srv, logr.New(errorSink{errs: &errs}), WithRetryQueueSize(3))
+	ctx := context.Background()
+
+	w.flush(ctx, records("a", "b"))
+	w.flush(ctx, records("c", "d"))
+	if len(errs) != 1 {
+		t.Fatalf("logged errors %v, want one for the dropped record", errs)
+	}
+
+	srv.setDown(false)
+	w.retryAt = time.Time{}
+	w.flush(ctx, nil)
+	if got := srv.names(); len(got) != 3 || got[0] != "b" || got[2] != "d" {
+		t.Errorf("written = %v, want [b c d]", got)
+	}
+}
+
+func TestWriterStartWritesRecordsLeft(t *testing.T) {
+	srv := &bulkServer{}
+	w := newTestWriter(t, srv, logr.Discard())
+	ctx, cancel := context.WithCancel(context.Background())
+	w.Enqueue(records("a")[0])
+	done := make(chan error)
+	go func() { done <- w.Start(ctx) }()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Start(...): %v", err)
+	}
+	if got := srv.names(); len(got) != 1 || got[0] != "a" {
+		t.Errorf("written = %v, want [a]", got)
+	}
+}
@@ -0,0 +1,123 @@
+// Package changelogs describes the changes the provider makes to OpenSearch
+// resources in the change log entries Crossplane records for them.
+package changelogs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/apis/changelogs/proto/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// DetailStateAfter is the additional detail of a change log entry
+	// that holds the state of the OpenSearch resource after the change, as
+	// JSON encoded Terraform attributes. The snapshot of the entry holds the
+	// managed resource, and thus the observed state, before the change.
+	DetailStateAfter = "stateAfter"
+
+	// DetailChangedAttributes is the additional detail of a change log
+	// entry that holds the comma-separated top-level Terraform attributes
+	// the change changed.
+	DetailChangedAttributes = "changedAttributes"
+
+	// DetailExternalName is the additional detail of a change log entry
+	// that holds the external name of the OpenSearch resource after the
+	// change. The snapshot of an entry of a create lacks the external name
+	// of resources whose identifier is chosen by OpenSearch.
+	DetailExternalName = "externalName"
+
+	// DetailDrift is the additional detail of a change log entry of an
+	// update that is "true" if the update corrected drift, i.e. restored
+	// an attribute of the OpenSearch resource to the value the provider
+	// last applied.
+	DetailDrift = "drift"
+
+	redacted = "(sensitive value)"
+)
+
+// Details returns the additional details of the change log entry of an
+// operation of the supplied Terraform resource: the state after the change
+// and the attributes it changed, and the external name of the resource. They
+// are read from the supplied resource data once the operation completed.
+// Sensitive attributes are redacted. The state after a deletion is empty.
+func Details(r *ujconfig.Resource, d *tfschema.ResourceData, deleted bool) managed.AdditionalDetails {
+	schema := r.TerraformResource.SchemaMap()
+	attrs := map[string]string{}
+	// The top-level attributes of the state, which external names are
+	// derived from.
+	tfstate := map[string]any{"id": d.Id()}
+	if s := d.State(); s != nil && !deleted {
+		for k, v := range s.Attributes {
+			if !strings.Contains(k, ".") {
+				tfstate[k] = v
+			}
+			if sensitive(schema, k) {
+				v = redacted
+			}
+			attrs[k] = v
+		}
+	}
+	var changed []string
+	for k := range schema {
+		if d.HasChange(k) {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	ad := managed.AdditionalDetails{DetailChangedAttributes: strings.Join(changed, ",")}
+	if b, err := json.Marshal(attrs); err == nil {
+		ad[DetailStateAfter] = string(b)
+	}
+	if fn := r.ExternalName.GetExternalNameFn; fn != nil && d.Id() != "" {
+		if name, err := fn(tfstate); err == nil {
+			ad[DetailExternalName] = name
+		}
+	}
+	return ad
+}
+
+// sensitive returns true if the attribute with the supplied flatmap key,
+// e.g. rule.0.password, is sensitive or nested in a sensitive attribute.
+func sensitive(schema map[string]*tfschema.Schema, key string) bool {
+	parts := strings.Split(key, ".")
+	// Every second part is the index of a list, set or map element.
+	for i := 0; i < len(parts); i += 2 {
+		s, ok := schema[parts[i]]
+		if !ok {
+			return false
+		}
+		if s.Sensitive {
+			return true
+		}
+		r, ok := s.Elem.(*tfschema.Resource)
+		if !ok {
+			return false
+		}
+		schema = r.SchemaMap()
+	}
+	return false
+}
+
+// Tee returns a ChangeLogger that records entries with all of the supplied
+// ChangeLoggers.
+func Tee(cls ...managed.ChangeLogger) managed.ChangeLogger {
+	return tee(cls)
+}
+
+type tee []managed.ChangeLogger
+
+func (t tee) Log(ctx context.Context, mg resource.Managed, op v1alpha1.OperationType, changeErr error, ad managed.AdditionalDetails) error {
+	errs := make([]error, 0, len(t))
+	for _, cl := range t {
+		errs = append(errs, cl.Log(ctx, mg, op, changeErr, ad))
+	}
+	return kerrors.NewAggregate(errs)
+}
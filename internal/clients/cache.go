@@ -0,0 +1,154 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	tfsdk "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+const (
+	errDigestConfiguration = "cannot compute digest of provider configuration"
+	errConfigureProvider   = "cannot configure the Terraform provider"
+)
+
+// A connection holds what the provider needs to talk to the OpenSearch
+// cluster of a ProviderConfig with one particular configuration.
+type connection struct {
+	key    ProviderConfigKey
+	digest string
+
+	mu   sync.Mutex
+	meta any
+	http *http.Client
+}
+
+// A connectionKey identifies an endpoint of a ProviderConfig.
+type connectionKey struct {
+	ProviderConfigKey
+
+	endpoint string
+}
+
+// A connectionCache caches a connection per endpoint of a ProviderConfig. A
+// connection is replaced as soon as the configuration of its ProviderConfig
+// changes, e.g. because a referenced Secret was rotated.
+type connectionCache struct {
+	mu          sync.Mutex
+	connections map[connectionKey]*connection
+}
+
+var connections = &connectionCache{connections: map[connectionKey]*connection{}}
+
+// get returns the connection of the supplied ProviderConfig for the supplied
+// configuration and the endpoint it uses.
+func (c *connectionCache) get(pcKey ProviderConfigKey, cfg terraform.ProviderConfiguration) (*connection, error) {
+	// Unlike ConfigurationDigest, the digest covers tokens, so that a
+	// renewed token results in a new connection.
+	digest, err := digestOf(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, errDigestConfiguration)
+	}
+
+	key := connectionKey{ProviderConfigKey: pcKey, endpoint: stringSetting(cfg, url)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conn, ok := c.connections[key]
+	if ok && conn.digest == digest {
+		return conn, nil
+	}
+	if ok {
+		conn.close()
+	}
+	conn = &connection{key: pcKey, digest: digest}
+	c.connections[key] = conn
+	return conn, nil
+}
+
+// evict the connections of the supplied ProviderConfig.
+func (c *connectionCache) evict(pcKey ProviderConfigKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, conn := range c.connections {
+		if key.ProviderConfigKey == pcKey {
+			conn.close()
+			delete(c.connections, key)
+		}
+	}
+}
+
+// providerMeta returns the meta of the Terraform provider configured with
+// the configuration of the connection. The provider is configured once per
+// connection, so that the Terraform provider keeps what it learns about the
+// cluster, e.g. its version, across reconciles.
+func (conn *connection) providerMeta(ctx context.Context, p *tfschema.Provider, cfg terraform.ProviderConfiguration) (any, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if conn.meta != nil {
+		return conn.meta, nil
+	}
+	// Configure a copy of the provider, which would otherwise hold the meta
+	// of whichever ProviderConfig was configured last.
+	pc := *p
+	if diags := pc.Configure(context.WithoutCancel(ctx), &tfsdk.ResourceConfig{Config: terraformSettings(cfg)}); diags.HasError() {
+		return nil, errors.Errorf("%s: %v", errConfigureProvider, diags)
+	}
+	conn.meta = pc.Meta()
+	return conn.meta, nil
+}
+
+// httpClient returns the HTTP client of the connection. If the
+// ProviderConfig configures several endpoints, its requests are recorded in
+// the health of the endpoint of the connection.
+func (conn *connection) httpClient(cfg terraform.ProviderConfiguration) (*http.Client, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if conn.http != nil {
+		return conn.http, nil
+	}
+	hc, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewHTTPClient)
+	}
+	hc.Transport = &metricsTransport{providerConfig: conn.key.String(), next: hc.Transport}
+	if len(endpointsOf(cfg)) > 0 {
+		hc.Transport = &endpointTransport{key: conn.key, endpoint: stringSetting(cfg, url), next: hc.Transport}
+	}
+	conn.http = hc
+	return hc, nil
+}
+
+// close the idle connections of the HTTP client of the connection.
+func (conn *connection) close() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if conn.http != nil {
+		conn.http.CloseIdleConnections()
+	}
+}
+
+// EvictConnection evicts the cached connections of the supplied
+// ProviderConfig.
+func EvictConnection(key ProviderConfigKey) {
+	connections.evict(key)
+}
+
+// HTTPClient returns an HTTP client that connects and authenticates to the
+// OpenSearch cluster of the supplied ProviderConfig and configuration, and
+// the URL of the endpoint it connects to.
+func HTTPClient(key ProviderConfigKey, cfg terraform.ProviderConfiguration) (*http.Client, string, error) {
+	cfg = endpoints.selectEndpoint(key, cfg)
+	conn, err := connections.get(key, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	hc, err := conn.httpClient(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return hc, stringSetting(cfg, url), nil
+}
@@ -0,0 +1,275 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+const (
+	errGetSecretValue    = "cannot get value of referenced Secret"
+	errInvalidSetting    = "invalid value of credentials setting %q"
+	errUnknownAuthType   = "unknown auth type %q"
+	errConfigureFromSpec = "cannot configure provider from ProviderConfig spec"
+
+	errInjectedIdentityAuth   = "auth type must be AWS if the credentials source is InjectedIdentity"
+	errInjectedIdentityRegion = "region is required if the credentials source is InjectedIdentity and url is not an AWS endpoint"
+)
+
+// credentialSettings are the provider settings read from the credentials.
+var credentialSettings = []string{
+	url, awsAccessKey, awsAssumeRoleArn, awsAssumeRoleExternalId, awsProfile, awsRegion,
+	awsSecretKey, awsSignatureService, awsToken, cacertFile, clientCertPath, clientKeyPath,
+	healthcheck, hostOverride, insecure, opensearchVersion, password, proxy, signAwsRequests,
+	sniff, token, tokenName, username, versionPingTimeout,
+}
+
+// authSettings are the provider settings that are ignored in the
+// credentials once the ProviderConfig configures its auth explicitly.
+var authSettings = []string{
+	awsAccessKey, awsAssumeRoleArn, awsAssumeRoleExternalId, awsProfile, awsRegion,
+	awsSecretKey, awsSignatureService, awsToken, password, signAwsRequests, token,
+	tokenName, username,
+}
+
+// Internal settings are not passed to the Terraform provider. Their names
+// start with internalSettingPrefix.
+const (
+	internalSettingPrefix = "_"
+
+	// tokenSourceSetting identifies the source of a token the provider
+	// obtained itself, so that renewing the token is not mistaken for a
+	// change of the configuration.
+	tokenSourceSetting = "_token_source"
+	// endpointsSetting lists the endpoints of a ProviderConfig that
+	// configures several endpoints. The url setting is set to one of them
+	// for each operation.
+	endpointsSetting = "_endpoints"
+	// endpointSelectionSetting is how the endpoint of an operation is
+	// selected.
+	endpointSelectionSetting = "_endpoint_selection"
+)
+
+var (
+	boolSettings = map[string]bool{healthcheck: true, insecure: true, signAwsRequests: true, sniff: true}
+	intSettings  = map[string]bool{versionPingTimeout: true}
+)
+
+// credentialsConfiguration returns the provider settings found in the
+// credentials of the supplied ProviderConfig spec.
+func credentialsConfiguration(ctx context.Context, kube client.Client, spec *namespacedv1beta1.ProviderConfigSpec) (terraform.ProviderConfiguration, error) {
+	cfg := terraform.ProviderConfiguration{}
+	switch spec.Credentials.Source {
+	case "", xpv1.CredentialsSourceNone:
+		return cfg, nil
+	case xpv1.CredentialsSourceInjectedIdentity:
+		// Requests are signed with the AWS credentials of the provider pod,
+		// e.g. the web identity of its ServiceAccount, which the default
+		// credential chain of the AWS SDK picks up.
+		cfg[signAwsRequests] = true
+		return cfg, nil
+	}
+
+	data, err := resource.CommonCredentialExtractor(ctx, spec.Credentials.Source, kube, spec.Credentials.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errExtractCredentials)
+	}
+	creds := map[string]any{}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalCredentials)
+	}
+
+	for _, setting := range credentialSettings {
+		value, ok := creds[setting]
+		if !ok {
+			continue
+		}
+		if cfg[setting], err = settingValue(setting, value); err != nil {
+			return nil, errors.Wrapf(err, errInvalidSetting, setting)
+		}
+	}
+	return cfg, nil
+}
+
+// settingValue converts a credentials value to the type of the provider
+// setting. Booleans and numbers may be given as JSON strings.
+func settingValue(setting string, value any) (any, error) {
+	switch {
+	case boolSettings[setting]:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case intSettings[setting]:
+		switch v := value.(type) {
+		case float64:
+			return int(v), nil
+		case string:
+			return strconv.Atoi(v)
+		}
+	default:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	}
+	return nil, errors.Errorf("unsupported type %T", value)
+}
+
+// configureFromSpec applies the typed fields of the supplied ProviderConfig
+// spec on top of the settings read from the credentials.
+func configureFromSpec(ctx context.Context, kube client.Client, spec *namespacedv1beta1.ProviderConfigSpec, cfg terraform.ProviderConfiguration) error {
+	setIfPresent(cfg, url, spec.URL)
+	if e := spec.Endpoints; e != nil && len(e.URLs) > 0 {
+		cfg[url] = e.URLs[0]
+		cfg[endpointsSetting] = e.URLs
+		cfg[endpointSelectionSetting] = string(namespacedv1beta1.EndpointSelectionFailover)
+		if e.Selection != nil {
+			cfg[endpointSelectionSetting] = string(*e.Selection)
+		}
+	}
+	setIfPresent(cfg, proxy, spec.Proxy)
+	setIfPresent(cfg, hostOverride, spec.HostOverride)
+	setIfPresent(cfg, opensearchVersion, spec.OpenSearchVersion)
+	setIfPresent(cfg, sniff, spec.Sniff)
+	setIfPresent(cfg, healthcheck, spec.Healthcheck)
+	if spec.TLS != nil {
+		setIfPresent(cfg, insecure, spec.TLS.InsecureSkipVerify)
+	}
+	if spec.Timeouts != nil && spec.Timeouts.VersionPing != nil {
+		cfg[versionPingTimeout] = max(1, int(spec.Timeouts.VersionPing.Round(time.Second).Seconds()))
+	}
+	if spec.Auth == nil {
+		return nil
+	}
+	return configureAuth(ctx, kube, spec.Auth, cfg)
+}
+
+// configureAuth replaces the authentication settings of the credentials
+// with the supplied auth configuration.
+func configureAuth(ctx context.Context, kube client.Client, a *namespacedv1beta1.AuthConfig, cfg terraform.ProviderConfiguration) error {
+	for _, setting := range authSettings {
+		delete(cfg, setting)
+	}
+	cfg[signAwsRequests] = false
+
+	switch a.Type {
+	case namespacedv1beta1.AuthTypeBasic:
+		if a.Basic == nil {
+			return nil
+		}
+		cfg[username] = a.Basic.Username
+		return setFromSecret(ctx, kube, cfg, password, &a.Basic.PasswordSecretRef)
+	case namespacedv1beta1.AuthTypeToken:
+		if a.Token == nil {
+			return nil
+		}
+		setIfPresent(cfg, tokenName, a.Token.Scheme)
+		return setFromSecret(ctx, kube, cfg, token, &a.Token.TokenSecretRef)
+	case namespacedv1beta1.AuthTypeAWS:
+		cfg[signAwsRequests] = true
+		if a.AWS == nil {
+			return nil
+		}
+		return configureAWSAuth(ctx, kube, a.AWS, cfg)
+	case namespacedv1beta1.AuthTypeMTLS:
+		return nil
+	case namespacedv1beta1.AuthTypeOIDC:
+		if a.OIDC == nil {
+			return nil
+		}
+		return configureOIDCAuth(ctx, kube, a.OIDC, cfg)
+	case namespacedv1beta1.AuthTypeServiceAccountToken:
+		if a.ServiceAccountToken == nil {
+			return nil
+		}
+		return configureServiceAccountTokenAuth(kube, a.ServiceAccountToken, cfg)
+	default:
+		return errors.Errorf(errUnknownAuthType, a.Type)
+	}
+}
+
+// configureAWSAuth configures the signing of requests to Amazon OpenSearch
+// Service.
+func configureAWSAuth(ctx context.Context, kube client.Client, a *namespacedv1beta1.AWSAuth, cfg terraform.ProviderConfiguration) error {
+	setIfPresent(cfg, awsRegion, a.Region)
+	setIfPresent(cfg, awsSignatureService, a.SignatureService)
+	setIfPresent(cfg, awsProfile, a.Profile)
+	setIfPresent(cfg, awsAssumeRoleArn, a.AssumeRoleARN)
+	setIfPresent(cfg, awsAssumeRoleExternalId, a.AssumeRoleExternalID)
+	for setting, ref := range map[string]*xpv1.SecretKeySelector{
+		awsAccessKey: a.AccessKeyIDSecretRef,
+		awsSecretKey: a.SecretAccessKeySecretRef,
+		awsToken:     a.SessionTokenSecretRef,
+	} {
+		if err := setFromSecret(ctx, kube, cfg, setting, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setIfPresent sets the supplied provider setting if value is not nil.
+func setIfPresent[T any](cfg terraform.ProviderConfiguration, setting string, value *T) {
+	if value != nil {
+		cfg[setting] = *value
+	}
+}
+
+// setFromSecret sets the supplied provider setting to the value of the
+// referenced Secret key if ref is not nil.
+func setFromSecret(ctx context.Context, kube client.Client, cfg terraform.ProviderConfiguration, setting string, ref *xpv1.SecretKeySelector) error {
+	if ref == nil {
+		return nil
+	}
+	data, err := resource.ExtractSecret(ctx, kube, xpv1.CommonCredentialSelectors{SecretRef: ref})
+	if err != nil {
+		return errors.Wrap(err, errGetSecretValue)
+	}
+	cfg[setting] = string(data)
+	return nil
+}
+
+// checkInjectedIdentity makes sure requests are signed with the AWS
+// credentials of the provider pod if the credentials source of the supplied
+// spec is InjectedIdentity.
+func checkInjectedIdentity(spec *namespacedv1beta1.ProviderConfigSpec, cfg terraform.ProviderConfiguration) error {
+	if spec.Credentials.Source != xpv1.CredentialsSourceInjectedIdentity {
+		return nil
+	}
+	if spec.Auth != nil && spec.Auth.Type != namespacedv1beta1.AuthTypeAWS {
+		return errors.New(errInjectedIdentityAuth)
+	}
+	u, err := neturl.Parse(stringSetting(cfg, url))
+	if err != nil {
+		return errors.Wrap(err, errParseURL)
+	}
+	if _, _, ok := awsSigning(cfg, u); !ok {
+		return errors.New(errInjectedIdentityRegion)
+	}
+	return nil
+}
+
+// terraformSettings returns the supplied configuration without the settings
+// that are internal to this provider.
+func terraformSettings(cfg terraform.ProviderConfiguration) terraform.ProviderConfiguration {
+	tf := make(terraform.ProviderConfiguration, len(cfg))
+	for k, v := range cfg {
+		if !strings.HasPrefix(k, internalSettingPrefix) {
+			tf[k] = v
+		}
+	}
+	return tf
+}
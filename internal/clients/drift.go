@@ -0,0 +1,86 @@
+package clients
+
+import (
+	"reflect"
+	"sync"
+
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// appliedStates remembers the top-level attributes the provider last applied
+// to the resource of each managed resource, to tell updates that correct
+// drift from updates that apply a changed desired state.
+type appliedStates struct {
+	mu     sync.Mutex
+	states map[types.UID]map[string]any
+}
+
+func newAppliedStates() *appliedStates {
+	return &appliedStates{states: map[types.UID]map[string]any{}}
+}
+
+// record the state of the supplied resource data, which was just applied
+// to the resource of the supplied managed resource.
+func (a *appliedStates) record(uid types.UID, tr *tfschema.Resource, d *tfschema.ResourceData) {
+	state := make(map[string]any, len(tr.Schema))
+	for k := range tr.Schema {
+		state[k] = normalize(d.Get(k))
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.states[uid] = state
+}
+
+// forget the state applied to the resource of the supplied managed
+// resource.
+func (a *appliedStates) forget(uid types.UID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.states, uid)
+}
+
+// drifted returns true if the supplied resource data of an update of the
+// resource of the supplied managed resource changes an attribute whose
+// desired value is the value last applied, i.e. one whose observed value
+// drifted from it. It returns false if it is not known what was last
+// applied, e.g. because the provider restarted since.
+func (a *appliedStates) drifted(uid types.UID, tr *tfschema.Resource, d *tfschema.ResourceData) bool {
+	a.mu.Lock()
+	applied, ok := a.states[uid]
+	a.mu.Unlock()
+	if !ok {
+		return false
+	}
+	for k := range tr.Schema {
+		if !d.HasChange(k) {
+			continue
+		}
+		if last, ok := applied[k]; ok && reflect.DeepEqual(last, normalize(d.Get(k))) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize returns the supplied attribute value with sets replaced by
+// lists of their elements, so that values can be compared.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case *tfschema.Set:
+		return normalize(v.List())
+	case []any:
+		l := make([]any, len(v))
+		for i, e := range v {
+			l[i] = normalize(e)
+		}
+		return l
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = normalize(e)
+		}
+		return m
+	}
+	return v
+}
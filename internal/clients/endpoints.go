@@ -0,0 +1,185 @@
+package clients
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+// EndpointHealth is the health of an endpoint, as of its last health check
+// or request.
+type EndpointHealth struct {
+	URL     string
+	Healthy bool
+	Message string
+
+	// LastSuccess is when a health check of or request to the endpoint last
+	// succeeded. It is zero if none succeeded yet.
+	LastSuccess time.Time
+}
+
+// An endpointSet tracks the health of the endpoints of a ProviderConfig.
+type endpointSet struct {
+	health map[string]EndpointHealth
+	next   int
+}
+
+// An endpointRegistry tracks the endpoints of each ProviderConfig that
+// configures several endpoints.
+type endpointRegistry struct {
+	mu   sync.Mutex
+	sets map[ProviderConfigKey]*endpointSet
+}
+
+var endpoints = &endpointRegistry{sets: map[ProviderConfigKey]*endpointSet{}}
+
+// endpointsOf returns the endpoints of the supplied configuration, if it
+// configures several endpoints.
+func endpointsOf(cfg terraform.ProviderConfiguration) []string {
+	urls, _ := cfg[endpointsSetting].([]string)
+	return urls
+}
+
+// withEndpoint returns a copy of the supplied configuration that uses the
+// supplied endpoint.
+func withEndpoint(cfg terraform.ProviderConfiguration, endpoint string) terraform.ProviderConfiguration {
+	c := make(terraform.ProviderConfiguration, len(cfg))
+	for k, v := range cfg {
+		c[k] = v
+	}
+	c[url] = endpoint
+	return c
+}
+
+// set returns the endpoint set of the supplied ProviderConfig. The caller
+// must hold the lock of the registry.
+func (r *endpointRegistry) set(key ProviderConfigKey) *endpointSet {
+	s, ok := r.sets[key]
+	if !ok {
+		s = &endpointSet{health: map[string]EndpointHealth{}}
+		r.sets[key] = s
+	}
+	return s
+}
+
+// selectEndpoint returns the supplied configuration with the url of the
+// endpoint the next operation should use. Endpoints that failed their last
+// health check or request are skipped unless all endpoints failed it.
+func (r *endpointRegistry) selectEndpoint(key ProviderConfigKey, cfg terraform.ProviderConfiguration) terraform.ProviderConfiguration {
+	urls := endpointsOf(cfg)
+	if len(urls) == 0 {
+		return cfg
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.set(key)
+	candidates := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if h, ok := s.health[u]; !ok || h.Healthy {
+			candidates = append(candidates, u)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = urls
+	}
+	if stringSetting(cfg, endpointSelectionSetting) != string(namespacedv1beta1.EndpointSelectionRoundRobin) {
+		return withEndpoint(cfg, candidates[0])
+	}
+	s.next = (s.next + 1) % len(candidates)
+	return withEndpoint(cfg, candidates[s.next])
+}
+
+// record the outcome of a health check of or request to an endpoint.
+func (r *endpointRegistry) record(key ProviderConfigKey, endpoint string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recordLocked(r.set(key), endpoint, err)
+}
+
+// recordRequest records the outcome of a request to an endpoint of a
+// ProviderConfig that configures several endpoints. It does nothing for
+// ProviderConfigs with a single endpoint, whose endpoints are never
+// selected.
+func (r *endpointRegistry) recordRequest(key ProviderConfigKey, endpoint string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.sets[key]; ok {
+		r.recordLocked(s, endpoint, err)
+	}
+}
+
+// recordLocked records the outcome of a health check of or request to an
+// endpoint of the supplied set. The caller must hold the lock of the
+// registry.
+func (r *endpointRegistry) recordLocked(s *endpointSet, endpoint string, err error) {
+	h := EndpointHealth{URL: endpoint, Healthy: err == nil, LastSuccess: s.health[endpoint].LastSuccess}
+	if err != nil {
+		h.Message = err.Error()
+	} else {
+		h.LastSuccess = time.Now()
+	}
+	s.health[endpoint] = h
+}
+
+// forget the endpoints of the supplied ProviderConfig.
+func (r *endpointRegistry) forget(key ProviderConfigKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sets, key)
+}
+
+// Endpoints returns the health of the endpoints the supplied configuration
+// of a ProviderConfig configures, in the order they are configured. It
+// returns nil unless the configuration configures several endpoints.
+func Endpoints(key ProviderConfigKey, cfg terraform.ProviderConfiguration) []EndpointHealth {
+	urls := endpointsOf(cfg)
+	if len(urls) == 0 {
+		return nil
+	}
+
+	endpoints.mu.Lock()
+	defer endpoints.mu.Unlock()
+	s := endpoints.set(key)
+	health := make([]EndpointHealth, 0, len(urls))
+	for _, u := range urls {
+		h, ok := s.health[u]
+		if !ok {
+			h = EndpointHealth{URL: u, Healthy: true}
+		}
+		health = append(health, h)
+	}
+	return health
+}
+
+// ForgetEndpoints forgets the health of the endpoints of the supplied
+// ProviderConfig.
+func ForgetEndpoints(key ProviderConfigKey) {
+	endpoints.forget(key)
+}
+
+// endpointTransport records the outcome of every request to an endpoint of
+// a ProviderConfig in the health of the endpoint. Requests fail the
+// endpoint if they cannot be sent or are answered with a server error.
+type endpointTransport struct {
+	key      ProviderConfigKey
+	endpoint string
+	next     http.RoundTripper
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil:
+		endpoints.recordRequest(t.key, t.endpoint, err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		endpoints.recordRequest(t.key, t.endpoint, &StatusError{Method: req.Method, Path: req.URL.Path, StatusCode: resp.StatusCode, Status: resp.Status})
+	default:
+		endpoints.recordRequest(t.key, t.endpoint, nil)
+	}
+	return resp, err
+}
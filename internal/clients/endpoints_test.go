@@ -0,0 +1,165 @@
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+// endpointsKey returns the key of a ProviderConfig whose endpoints are
+// forgotten once the test finished.
+func endpointsKey(t *testing.T) ProviderConfigKey {
+	t.Helper()
+	key := ProviderConfigKey{GroupKind: schema.GroupKind{Group: "opensearch.upbound.io", Kind: "ProviderConfig"}, Name: t.Name()}
+	t.Cleanup(func() { ForgetEndpoints(key) })
+	return key
+}
+
+func endpointsConfig(selection namespacedv1beta1.EndpointSelection, urls ...string) terraform.ProviderConfiguration {
+	return terraform.ProviderConfiguration{url: urls[0], endpointsSetting: urls, endpointSelectionSetting: string(selection)}
+}
+
+func TestSelectEndpoint(t *testing.T) {
+	errDown := errors.New("down")
+	cases := map[string]struct {
+		selection namespacedv1beta1.EndpointSelection
+		failed    []string
+		want      []string
+	}{
+		"FailoverFirstHealthy": {
+			selection: namespacedv1beta1.EndpointSelectionFailover,
+			want:      []string{"https://a:9200", "https://a:9200", "https://a:9200"},
+		},
+		"FailoverSkipsFailed": {
+			selection: namespacedv1beta1.EndpointSelectionFailover,
+			failed:    []string{"https://a:9200"},
+			want:      []string{"https://b:9200", "https://b:9200"},
+		},
+		"FailoverAllFailed": {
+			selection: namespacedv1beta1.EndpointSelectionFailover,
+			failed:    []string{"https://a:9200", "https://b:9200", "https://c:9200"},
+			want:      []string{"https://a:9200"},
+		},
+		"RoundRobin": {
+			selection: namespacedv1beta1.EndpointSelectionRoundRobin,
+			want:      []string{"https://b:9200", "https://c:9200", "https://a:9200", "https://b:9200"},
+		},
+		"RoundRobinSkipsFailed": {
+			selection: namespacedv1beta1.EndpointSelectionRoundRobin,
+			failed:    []string{"https://b:9200"},
+			want:      []string{"https://c:9200", "https://a:9200", "https://c:9200"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			key := endpointsKey(t)
+			cfg := endpointsConfig(tc.selection, "https://a:9200", "https://b:9200", "https://c:9200")
+			for _, u := range tc.failed {
+				endpoints.record(key, u, errDown)
+			}
+			for i, want := range tc.want {
+				if got := stringSetting(endpoints.selectEndpoint(key, cfg), url); got != want {
+					t.Errorf("selectEndpoint(...) #%d: want %s, got %s", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectEndpointSingle(t *testing.T) {
+	key := endpointsKey(t)
+	cfg := terraform.ProviderConfiguration{url: "https://opensearch:9200"}
+	if got := endpoints.selectEndpoint(key, cfg); stringSetting(got, url) != "https://opensearch:9200" {
+		t.Errorf("selectEndpoint(...): want the url unchanged, got %v", got)
+	}
+	endpoints.recordRequest(key, "https://opensearch:9200", errors.New("down"))
+	if got := Endpoints(key, cfg); got != nil {
+		t.Errorf("Endpoints(...): want nil for a single endpoint, got %v", got)
+	}
+}
+
+func TestEndpointTransport(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	down := "http://127.0.0.1:1"
+
+	key := endpointsKey(t)
+	cfg := endpointsConfig(namespacedv1beta1.EndpointSelectionFailover, srv.URL, down)
+	// Selecting an endpoint starts tracking the endpoints.
+	endpoints.selectEndpoint(key, cfg)
+
+	send := func(endpoint string) {
+		t.Helper()
+		hc := &http.Client{Transport: &endpointTransport{key: key, endpoint: endpoint, next: http.DefaultTransport}}
+		resp, err := hc.Get(endpoint + "/")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}
+	health := func() map[string]EndpointHealth {
+		t.Helper()
+		h := map[string]EndpointHealth{}
+		for _, e := range Endpoints(key, cfg) {
+			h[e.URL] = e
+		}
+		return h
+	}
+
+	send(srv.URL)
+	send(down)
+	h := health()
+	if !h[srv.URL].Healthy || h[srv.URL].LastSuccess.IsZero() {
+		t.Errorf("%s: want healthy after a successful request, got %+v", srv.URL, h[srv.URL])
+	}
+	if h[down].Healthy || h[down].Message == "" || !h[down].LastSuccess.IsZero() {
+		t.Errorf("%s: want unhealthy after a failed request, got %+v", down, h[down])
+	}
+
+	// Client errors are answered by a working endpoint.
+	status = http.StatusNotFound
+	send(srv.URL)
+	if h := health(); !h[srv.URL].Healthy {
+		t.Errorf("%s: want healthy after a client error, got %+v", srv.URL, h[srv.URL])
+	}
+
+	lastSuccess := health()[srv.URL].LastSuccess
+	status = http.StatusServiceUnavailable
+	send(srv.URL)
+	h = health()
+	if h[srv.URL].Healthy {
+		t.Errorf("%s: want unhealthy after a server error, got %+v", srv.URL, h[srv.URL])
+	}
+	if !h[srv.URL].LastSuccess.Equal(lastSuccess) {
+		t.Errorf("%s: want the last success kept after a failure, got %v", srv.URL, h[srv.URL].LastSuccess)
+	}
+}
+
+func TestEndpointFailed(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"ServerError":       {err: &StatusError{Method: http.MethodGet, Path: "/", StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}, want: true},
+		"ElasticServer":     {err: errors.New("error creating index logs: elastic: Error 503 (Service Unavailable)"), want: true},
+		"ClientError":       {err: errors.New("elastic: Error 400 (Bad Request): invalid mapping"), want: false},
+		"ConnectionRefused": {err: errors.New(`Get "https://a:9200/": dial tcp 10.0.0.1:9200: connect: connection refused`), want: true},
+		"NoNode":            {err: errors.New("no available connection: no Elasticsearch node available"), want: true},
+		"Validation":        {err: errors.New(`"policy_id": required field is not set`), want: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := endpointFailed(tc.err); got != tc.want {
+				t.Errorf("endpointFailed(%q): want %t, got %t", tc.err, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const (
+	// attributeFinalPipeline is the attribute the configuration of indices
+	// adds for their final pipeline.
+	attributeFinalPipeline = "final_pipeline"
+	settingFinalPipeline   = "index.final_pipeline"
+
+	indexSettingsPathTemplate = "/{index}/_settings"
+
+	errGetFinalPipeline = "cannot get the final pipeline of index %s"
+	errSetFinalPipeline = "cannot set the final pipeline of index %s"
+	errCLIUnsupported   = "%s is only supported by the no-fork Terraform runner"
+	errNoResourceMeta   = "the operation does not run for a managed resource"
+)
+
+// extensions extend Terraform resources with attributes the Terraform
+// provider does not support. The attributes are added to the schemas of the
+// resources by their configuration. The operations of extended resources
+// are called with the meta of the managed resource they run for.
+var extensions = map[string]func(*tfschema.Resource){
+	"opensearch_index": extendIndex,
+}
+
+// extensionAttributes are the attributes the extensions add, by Terraform
+// resource.
+var extensionAttributes = map[string][]string{
+	"opensearch_index": {attributeFinalPipeline},
+}
+
+// RejectExtensions configures the Terraform resources of the supplied
+// provider to reject the attributes their extensions add. Only the no-fork
+// runner calls the Terraform provider in process, so that the Terraform CLI
+// of the other runners would fail on attributes it does not know. It must
+// be called before the provider is used.
+func RejectExtensions(p *ujconfig.Provider) {
+	for _, r := range p.Resources {
+		if attrs, ok := extensionAttributes[r.Name]; ok {
+			r.TerraformConversions = append(r.TerraformConversions, rejectAttributes(attrs))
+		}
+	}
+}
+
+// rejectAttributes is a Terraform conversion that fails if any of its
+// attributes is set, and removes them otherwise.
+type rejectAttributes []string
+
+func (c rejectAttributes) Convert(params map[string]any, _ *ujconfig.Resource, mode ujconfig.Mode) (map[string]any, error) {
+	if mode != ujconfig.ToTerraform {
+		return params, nil
+	}
+	for _, a := range c {
+		if v, ok := params[a]; ok && v != nil && v != "" {
+			return nil, errors.Errorf(errCLIUnsupported, a)
+		}
+		delete(params, a)
+	}
+	return params, nil
+}
+
+// extendIndex extends indices with their final pipeline, an index setting
+// the Terraform provider does not know.
+func extendIndex(tr *tfschema.Resource) {
+	create, read, update := tr.CreateContext, tr.ReadContext, tr.UpdateContext
+	if create == nil || read == nil || update == nil {
+		return
+	}
+	tr.CreateContext = func(ctx context.Context, d *tfschema.ResourceData, meta any) diag.Diagnostics {
+		if diags := create(ctx, d, meta); diags.HasError() || d.Id() == "" {
+			return diags
+		}
+		if p, ok := d.GetOk(attributeFinalPipeline); ok {
+			if err := putFinalPipeline(ctx, meta, d.Id(), p); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		return diag.FromErr(readFinalPipeline(ctx, d, meta))
+	}
+	tr.ReadContext = func(ctx context.Context, d *tfschema.ResourceData, meta any) diag.Diagnostics {
+		if diags := read(ctx, d, meta); diags.HasError() || d.Id() == "" {
+			return diags
+		}
+		return diag.FromErr(readFinalPipeline(ctx, d, meta))
+	}
+	tr.UpdateContext = func(ctx context.Context, d *tfschema.ResourceData, meta any) diag.Diagnostics {
+		if diags := update(ctx, d, meta); diags.HasError() || d.Id() == "" {
+			return diags
+		}
+		if d.HasChange(attributeFinalPipeline) {
+			// A nil pipeline removes the setting.
+			var p any
+			if v := d.Get(attributeFinalPipeline).(string); v != "" {
+				p = v
+			}
+			if err := putFinalPipeline(ctx, meta, d.Id(), p); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		return diag.FromErr(readFinalPipeline(ctx, d, meta))
+	}
+}
+
+// httpClientOf returns the HTTP client the supplied meta of a managed
+// resource carries, and the URL of the endpoint it connects to.
+func httpClientOf(meta any) (*http.Client, string, error) {
+	m, ok := meta.(*resourceMeta)
+	if !ok {
+		return nil, "", errors.New(errNoResourceMeta)
+	}
+	return m.http, m.endpoint, nil
+}
+
+type indexSettingsResponse map[string]struct {
+	Settings map[string]any `json:"settings"`
+}
+
+// readFinalPipeline reads the final pipeline of the index of the supplied
+// resource data into it.
+func readFinalPipeline(ctx context.Context, d *tfschema.ResourceData, meta any) error {
+	hc, base, err := httpClientOf(meta)
+	if err != nil {
+		return errors.Wrapf(err, errGetFinalPipeline, d.Id())
+	}
+	path := "/" + neturl.PathEscape(d.Id()) + "/_settings/" + settingFinalPipeline + "?flat_settings=true"
+	req, err := http.NewRequestWithContext(WithPathTemplate(ctx, indexSettingsPathTemplate), http.MethodGet, strings.TrimSuffix(base, "/")+path, nil)
+	if err != nil {
+		return errors.Wrapf(err, errGetFinalPipeline, d.Id())
+	}
+	res := indexSettingsResponse{}
+	if err := doJSON(hc, req, &res); err != nil {
+		return errors.Wrapf(err, errGetFinalPipeline, d.Id())
+	}
+	p := ""
+	// The response is keyed by the concrete index, which differs from the
+	// ID of indices created with date math.
+	for _, idx := range res {
+		if v, ok := idx.Settings[settingFinalPipeline].(string); ok {
+			p = v
+		}
+	}
+	return d.Set(attributeFinalPipeline, p)
+}
+
+// putFinalPipeline sets the final pipeline of the supplied index. A nil
+// pipeline removes it.
+func putFinalPipeline(ctx context.Context, meta any, index string, pipeline any) error {
+	hc, base, err := httpClientOf(meta)
+	if err != nil {
+		return errors.Wrapf(err, errSetFinalPipeline, index)
+	}
+	body, err := json.Marshal(map[string]any{settingFinalPipeline: pipeline})
+	if err != nil {
+		return errors.Wrapf(err, errSetFinalPipeline, index)
+	}
+	path := "/" + neturl.PathEscape(index) + "/_settings"
+	req, err := http.NewRequestWithContext(WithPathTemplate(ctx, indexSettingsPathTemplate), http.MethodPut, strings.TrimSuffix(base, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, errSetFinalPipeline, index)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return errors.Wrapf(doJSON(hc, req, nil), errSetFinalPipeline, index)
+}
+
+// doJSON sends the supplied request and decodes its JSON response into the
+// supplied value, unless it is nil.
+func doJSON(hc *http.Client, req *http.Request, into any) error {
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return &StatusError{Method: req.Method, Path: req.URL.Path, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if into == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(into), errDecodeBody)
+}
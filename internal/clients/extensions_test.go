@@ -0,0 +1,122 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tagesjump/provider-opensearch/apis/cluster/opensearch/v1alpha1"
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+)
+
+func TestExtendIndex(t *testing.T) {
+	var mu sync.Mutex
+	settings := map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case req.Method == http.MethodPut && req.URL.Path == "/logs/_settings":
+			_ = json.NewDecoder(req.Body).Decode(&settings)
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		case req.Method == http.MethodGet && req.URL.Path == "/logs/_settings/"+settingFinalPipeline:
+			_ = json.NewEncoder(w).Encode(map[string]any{"logs-000001": map[string]any{"settings": settings}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	// The Terraform provider creates indices with a legacy operation.
+	tr := &tfschema.Resource{
+		Schema: map[string]*tfschema.Schema{
+			"name":                 {Type: tfschema.TypeString, Required: true},
+			attributeFinalPipeline: {Type: tfschema.TypeString, Optional: true},
+		},
+		Create: func(d *tfschema.ResourceData, meta any) error {
+			if meta != "provider meta" {
+				t.Errorf("Create(...): meta = %v, want the meta of the Terraform provider", meta)
+			}
+			d.SetId(d.Get("name").(string))
+			return nil
+		},
+		Read:   func(*tfschema.ResourceData, any) error { return nil },
+		Update: func(*tfschema.ResourceData, any) error { return nil },
+		Delete: func(*tfschema.ResourceData, any) error { return nil },
+	}
+	r := &ujconfig.Resource{Name: "opensearch_index", Kind: "Index", TerraformResource: tr}
+	WrapOperations(&ujconfig.Provider{Resources: map[string]*ujconfig.Resource{r.Name: r}})
+
+	key := ProviderConfigKey{Name: "extensions"}
+	meta := &resourceMeta{
+		provider: "provider meta",
+		key:      key,
+		limiter:  limiters.limiter(key, nil),
+		managed:  &v1alpha1.Index{ObjectMeta: metav1.ObjectMeta{Name: "logs", UID: "uid"}},
+		http:     &http.Client{Transport: &metricsTransport{providerConfig: key.String(), next: http.DefaultTransport}},
+		endpoint: srv.URL,
+	}
+	d := tr.TestResourceData()
+	_ = d.Set("name", "logs")
+	_ = d.Set(attributeFinalPipeline, "enrich")
+	if diags := tr.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("CreateContext(...): %v", diags)
+	}
+	if got := settings[settingFinalPipeline]; got != "enrich" {
+		t.Errorf("final pipeline set to %v, want enrich", got)
+	}
+	if got := d.Get(attributeFinalPipeline); got != "enrich" {
+		t.Errorf("final pipeline read as %v, want enrich", got)
+	}
+	// The requests are sent with the context of the operation.
+	if got := testutil.ToFloat64(metrics.Requests.WithLabelValues(http.MethodGet, indexSettingsPathTemplate, "200", key.String(), "Index")); got != 1 {
+		t.Errorf("requests = %v, want 1", got)
+	}
+
+	if diags := tr.CreateContext(context.Background(), d, "provider meta"); !diags.HasError() {
+		t.Error("CreateContext(...): want an error without the meta of a managed resource")
+	}
+}
+
+func TestRejectExtensions(t *testing.T) {
+	cases := map[string]struct {
+		params  map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		"Set": {
+			params:  map[string]any{"name": "logs", attributeFinalPipeline: "enrich"},
+			wantErr: true,
+		},
+		"Empty": {
+			params: map[string]any{"name": "logs", attributeFinalPipeline: ""},
+			want:   map[string]any{"name": "logs"},
+		},
+		"NotSet": {
+			params: map[string]any{"name": "logs"},
+			want:   map[string]any{"name": "logs"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &ujconfig.Resource{Name: "opensearch_index"}
+			RejectExtensions(&ujconfig.Provider{Resources: map[string]*ujconfig.Resource{r.Name: r}})
+			got, err := r.ApplyTFConversions(tc.params, ujconfig.ToTerraform)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ApplyTFConversions(...): error = %v, want error %t", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("ApplyTFConversions(...) = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+)
+
+const (
+	defaultTokenName        = "ApiKey"
+	defaultSignatureService = "es"
+	serverlessService       = "aoss"
+
+	errParseURL        = "cannot parse url"
+	errParseProxy      = "cannot parse proxy url"
+	errReadCACert      = "cannot read CA certificate"
+	errNoCACert        = "no certificate found in CA bundle"
+	errLoadClientCert  = "cannot load client certificate"
+	errAWSSession      = "cannot create AWS session"
+	errSignRequest     = "cannot sign request"
+	errReadRequestBody = "cannot read request body"
+)
+
+var (
+	awsURLRegexp           = regexp.MustCompile(`([a-z0-9-]+).es.amazonaws.com$`)
+	awsServerlessURLRegexp = regexp.MustCompile(`([a-z0-9-]+).aoss.amazonaws.com$`)
+)
+
+// newHTTPClient returns an HTTP client that connects and authenticates to
+// OpenSearch the way the Terraform provider does for the supplied provider
+// configuration.
+func newHTTPClient(cfg terraform.ProviderConfiguration) (*http.Client, error) {
+	u, err := neturl.Parse(stringSetting(cfg, url))
+	if err != nil {
+		return nil, errors.Wrap(err, errParseURL)
+	}
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var rt http.RoundTripper = transport
+	if region, service, ok := awsSigning(cfg, u); ok {
+		if rt, err = newAWSSigningTransport(cfg, region, service, rt); err != nil {
+			return nil, err
+		}
+	} else {
+		h := http.Header{}
+		if user, pass := stringSetting(cfg, username), stringSetting(cfg, password); user != "" && pass != "" {
+			h.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+pass)))
+		}
+		if t := stringSetting(cfg, token); t != "" {
+			name := stringSetting(cfg, tokenName)
+			if name == "" {
+				name = defaultTokenName
+			}
+			h.Set("Authorization", fmt.Sprintf("%s %s", name, t))
+		}
+		rt = &headerTransport{header: h, next: rt}
+	}
+	if host := stringSetting(cfg, hostOverride); host != "" {
+		rt = &headerTransport{host: host, next: rt}
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// newTransport returns the TLS and proxy aware transport of a client.
+func newTransport(cfg terraform.ProviderConfiguration) (*http.Transport, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if ca := stringSetting(cfg, cacertFile); ca != "" {
+		pem, err := readPathOrContent(ca)
+		if err != nil {
+			return nil, errors.Wrap(err, errReadCACert)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New(errNoCACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cert, key := stringSetting(cfg, clientCertPath), stringSetting(cfg, clientKeyPath); cert != "" && key != "" {
+		certPEM, err := readPathOrContent(cert)
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadClientCert)
+		}
+		keyPEM, err := readPathOrContent(key)
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadClientCert)
+		}
+		pair, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadClientCert)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+	if boolSetting(cfg, insecure) {
+		tlsConfig.InsecureSkipVerify = true
+	} else if host := stringSetting(cfg, hostOverride); host != "" {
+		tlsConfig.ServerName = host
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if p := stringSetting(cfg, proxy); p != "" {
+		proxyURL, err := neturl.Parse(p)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseProxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return transport, nil
+}
+
+// awsSigning returns the region and service requests are signed for, if
+// they are signed at all. It follows the rules of the Terraform provider.
+func awsSigning(cfg terraform.ProviderConfiguration, u *neturl.URL) (string, string, bool) {
+	if v, ok := cfg[signAwsRequests].(bool); ok && !v {
+		return "", "", false
+	}
+	service := stringSetting(cfg, awsSignatureService)
+	if service == "" {
+		service = defaultSignatureService
+	}
+	region := stringSetting(cfg, awsRegion)
+	switch {
+	case awsURLRegexp.MatchString(u.Hostname()):
+		return awsURLRegexp.FindStringSubmatch(u.Hostname())[1], service, true
+	case awsServerlessURLRegexp.MatchString(u.Hostname()):
+		return awsServerlessURLRegexp.FindStringSubmatch(u.Hostname())[1], serverlessService, true
+	case region != "":
+		return region, service, true
+	}
+	return "", "", false
+}
+
+// newAWSSigningTransport returns a transport that signs requests with the
+// AWS credentials of the supplied configuration. Static keys take priority
+// over an assumed role, which takes priority over a profile and the default
+// credential chain.
+func newAWSSigningTransport(cfg terraform.ProviderConfiguration, region, service string, next http.RoundTripper) (http.RoundTripper, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config: aws.Config{
+			Region:     aws.String(region),
+			HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		},
+		Profile:           stringSetting(cfg, awsProfile),
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errAWSSession)
+	}
+
+	creds := sess.Config.Credentials
+	if key := stringSetting(cfg, awsAccessKey); key != "" {
+		creds = credentials.NewStaticCredentials(key, stringSetting(cfg, awsSecretKey), stringSetting(cfg, awsToken))
+	} else if arn := stringSetting(cfg, awsAssumeRoleArn); arn != "" {
+		creds = stscreds.NewCredentials(sess, arn, func(p *stscreds.AssumeRoleProvider) {
+			if id := stringSetting(cfg, awsAssumeRoleExternalId); id != "" {
+				p.ExternalID = aws.String(id)
+			}
+		})
+	}
+	return &awsSigningTransport{signer: v4.NewSigner(creds), region: region, service: service, next: next}, nil
+}
+
+// awsSigningTransport signs requests with AWS Signature Version 4.
+type awsSigningTransport struct {
+	signer  *v4.Signer
+	region  string
+	service string
+	next    http.RoundTripper
+}
+
+func (t *awsSigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	var body io.ReadSeeker
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, errReadRequestBody)
+		}
+		body = bytes.NewReader(b)
+	}
+	if _, err := t.signer.Sign(req, body, t.service, t.region, time.Now()); err != nil {
+		return nil, errors.Wrap(err, errSignRequest)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// headerTransport sets headers and the host of requests.
+type headerTransport struct {
+	header http.Header
+	host   string
+	next   http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.header {
+		req.Header[k] = v
+	}
+	if t.host != "" {
+		req.Host = t.host
+	}
+	return t.next.RoundTrip(req)
+}
+
+// pathOther is the path requests without a path template are reported with
+// in the metrics, as paths hold the names of resources.
+const pathOther = "other"
+
+type pathTemplateKey struct{}
+
+// WithPathTemplate returns a context for requests to the supplied path
+// template, e.g. /{index}/_doc, which is reported in the metrics of the
+// requests instead of their path.
+func WithPathTemplate(ctx context.Context, template string) context.Context {
+	return context.WithValue(ctx, pathTemplateKey{}, template)
+}
+
+type kindKey struct{}
+
+// withKind returns a context for requests sent by an operation of a managed
+// resource of the supplied kind.
+func withKind(ctx context.Context, kind string) context.Context {
+	return context.WithValue(ctx, kindKey{}, kind)
+}
+
+// metricsTransport records metrics of the requests sent for a
+// ProviderConfig with the HTTP clients of the provider. The Terraform
+// provider sends its requests with its own clients, which are not
+// instrumented. Requests are reported with the kind of the managed
+// resource whose operation sent them, if any, and with their path
+// template, or pathOther if they have none.
+type metricsTransport struct {
+	providerConfig string
+	next           http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	path, ok := req.Context().Value(pathTemplateKey{}).(string)
+	if !ok {
+		path = pathOther
+	}
+	kind, _ := req.Context().Value(kindKey{}).(string)
+	labels := prometheus.Labels{"method": req.Method, "path": path, "code": code, "provider_config": t.providerConfig, "kind": kind}
+	metrics.Requests.With(labels).Inc()
+	metrics.RequestDuration.With(labels).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// readPathOrContent returns the supplied PEM content, or the content of the
+// file at the supplied path.
+func readPathOrContent(s string) ([]byte, error) {
+	if strings.Contains(s, pemBlockPrefix) {
+		return []byte(s), nil
+	}
+	return os.ReadFile(filepath.Clean(s))
+}
+
+// stringSetting returns the supplied string setting, or an empty string.
+func stringSetting(cfg terraform.ProviderConfiguration, setting string) string {
+	s, _ := cfg[setting].(string)
+	return s
+}
+
+// boolSetting returns the supplied boolean setting, or false.
+func boolSetting(cfg terraform.ProviderConfiguration, setting string) bool {
+	b, _ := cfg[setting].(bool)
+	return b
+}
@@ -0,0 +1,52 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+)
+
+func TestMetricsTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	hc := &http.Client{Transport: &metricsTransport{providerConfig: "ProviderConfig.opensearch.upbound.io/metrics", next: http.DefaultTransport}}
+
+	cases := map[string]struct {
+		ctx  context.Context
+		path string
+		kind string
+	}{
+		"Operation": {
+			ctx:  withKind(WithPathTemplate(context.Background(), indexSettingsPathTemplate), "Index"),
+			path: indexSettingsPathTemplate,
+			kind: "Index",
+		},
+		"NoTemplate": {
+			ctx:  context.Background(),
+			path: pathOther,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(tc.ctx, http.MethodGet, srv.URL+"/logs-2026.10.17/_settings", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := hc.Do(req)
+			if err != nil {
+				t.Fatalf("Do(...): %v", err)
+			}
+			_ = resp.Body.Close()
+			if got := testutil.ToFloat64(metrics.Requests.WithLabelValues(http.MethodGet, tc.path, "404", "ProviderConfig.opensearch.upbound.io/metrics", tc.kind)); got != 1 {
+				t.Errorf("requests = %v, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+)
+
+const (
+	errWaitForOperation = "cannot wait for the limits of the ProviderConfig"
+)
+
+// An operationLimiter enforces the limits of a ProviderConfig.
+type operationLimiter struct {
+	limits namespacedv1beta1.LimitsConfig
+
+	// slots holds a token per running operation. It is nil if the number
+	// of concurrent operations is not limited.
+	slots chan struct{}
+
+	// rate is nil if the rate of operations is not limited.
+	rate *rate.Limiter
+
+	labels prometheus.Labels
+}
+
+func newOperationLimiter(key ProviderConfigKey, l namespacedv1beta1.LimitsConfig) *operationLimiter {
+	ol := &operationLimiter{
+		limits: l,
+		labels: prometheus.Labels{"provider_config": key.String()},
+	}
+	if l.MaxConcurrentOperations != nil {
+		ol.slots = make(chan struct{}, *l.MaxConcurrentOperations)
+	}
+	if l.OperationsPerSecond != nil {
+		burst := *l.OperationsPerSecond
+		if l.Burst != nil {
+			burst = *l.Burst
+		}
+		ol.rate = rate.NewLimiter(rate.Limit(*l.OperationsPerSecond), int(burst))
+	}
+	return ol
+}
+
+// acquire waits until the limits allow another operation to start, and
+// returns a function that must be called once the operation finished.
+func (ol *operationLimiter) acquire(ctx context.Context) (func(), error) {
+	start := time.Now()
+	metrics.OperationsWaiting.With(ol.labels).Inc()
+	err := ol.wait(ctx)
+	metrics.OperationsWaiting.With(ol.labels).Dec()
+	metrics.OperationWait.With(ol.labels).Observe(time.Since(start).Seconds())
+	if err != nil {
+		return nil, errors.Wrap(err, errWaitForOperation)
+	}
+
+	metrics.OperationsInFlight.With(ol.labels).Inc()
+	return func() {
+		metrics.OperationsInFlight.With(ol.labels).Dec()
+		if ol.slots != nil {
+			<-ol.slots
+		}
+	}, nil
+}
+
+// wait until the rate limit and a free slot allow another operation to
+// start. The rate limit is waited for first, so that operations that wait
+// for it do not hold a slot.
+func (ol *operationLimiter) wait(ctx context.Context) error {
+	if ol.rate != nil {
+		if err := ol.rate.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if ol.slots == nil {
+		return nil
+	}
+	select {
+	case ol.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// A limiterRegistry tracks the limiter of each ProviderConfig.
+type limiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[ProviderConfigKey]*operationLimiter
+}
+
+var limiters = &limiterRegistry{limiters: map[ProviderConfigKey]*operationLimiter{}}
+
+// limiter returns the limiter of the ProviderConfig with the supplied key and
+// limits. The limiter of the ProviderConfig is replaced if its limits
+// changed. Operations that already run are not counted against the new
+// limiter.
+func (r *limiterRegistry) limiter(key ProviderConfigKey, l *namespacedv1beta1.LimitsConfig) *operationLimiter {
+	limits := namespacedv1beta1.LimitsConfig{}
+	if l != nil {
+		limits = *l
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ol, ok := r.limiters[key]; ok && equalLimits(ol.limits, limits) {
+		return ol
+	}
+	ol := newOperationLimiter(key, limits)
+	r.limiters[key] = ol
+	return ol
+}
+
+// forget the limiter of the supplied ProviderConfig.
+func (r *limiterRegistry) forget(key ProviderConfigKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.limiters, key)
+}
+
+func equalLimits(a, b namespacedv1beta1.LimitsConfig) bool {
+	eq := func(x, y *int32) bool {
+		return (x == nil && y == nil) || (x != nil && y != nil && *x == *y)
+	}
+	return eq(a.MaxConcurrentOperations, b.MaxConcurrentOperations) &&
+		eq(a.OperationsPerSecond, b.OperationsPerSecond) &&
+		eq(a.Burst, b.Burst)
+}
+
+// ForgetLimits forgets the limiter of the supplied ProviderConfig.
+func ForgetLimits(key ProviderConfigKey) {
+	limiters.forget(key)
+}
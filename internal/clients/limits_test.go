@@ -0,0 +1,145 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/tagesjump/provider-opensearch/apis/cluster/opensearch/v1alpha1"
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+	"github.com/tagesjump/provider-opensearch/internal/health"
+)
+
+func limitsKey(t *testing.T) ProviderConfigKey {
+	t.Helper()
+	key := ProviderConfigKey{GroupKind: schema.GroupKind{Group: "opensearch.upbound.io", Kind: "ProviderConfig"}, Name: t.Name()}
+	t.Cleanup(func() { ForgetLimits(key) })
+	return key
+}
+
+func TestOperationLimiterConcurrency(t *testing.T) {
+	ol := newOperationLimiter(limitsKey(t), namespacedv1beta1.LimitsConfig{MaxConcurrentOperations: ptr.To[int32](1)})
+
+	release, err := ol.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire(...): %v", err)
+	}
+	// A second operation waits for the first to finish.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := ol.acquire(ctx); err == nil {
+		t.Fatal("acquire(...): want error while the only slot is held, got nil")
+	}
+
+	release()
+	release, err = ol.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire(...) after release: %v", err)
+	}
+	release()
+}
+
+func TestOperationLimiterRate(t *testing.T) {
+	ol := newOperationLimiter(limitsKey(t), namespacedv1beta1.LimitsConfig{OperationsPerSecond: ptr.To[int32](1), Burst: ptr.To[int32](2)})
+
+	// The burst starts right away.
+	for i := range 2 {
+		release, err := ol.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("acquire(...) #%d: %v", i, err)
+		}
+		release()
+	}
+	// The next operation waits for the rate, which exceeds the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := ol.acquire(ctx); err == nil {
+		t.Error("acquire(...): want error once the burst is used, got nil")
+	}
+}
+
+func TestOperationLimiterUnlimited(t *testing.T) {
+	ol := newOperationLimiter(limitsKey(t), namespacedv1beta1.LimitsConfig{})
+	releases := make([]func(), 0, 100)
+	for i := range 100 {
+		release, err := ol.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("acquire(...) #%d: %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+	for _, release := range releases {
+		release()
+	}
+}
+
+func TestLimiterRegistry(t *testing.T) {
+	key := limitsKey(t)
+	l := &namespacedv1beta1.LimitsConfig{MaxConcurrentOperations: ptr.To[int32](2)}
+
+	first := limiters.limiter(key, l)
+	if got := limiters.limiter(key, &namespacedv1beta1.LimitsConfig{MaxConcurrentOperations: ptr.To[int32](2)}); got != first {
+		t.Error("limiter(...): want the limiter reused for equal limits")
+	}
+	changed := limiters.limiter(key, &namespacedv1beta1.LimitsConfig{MaxConcurrentOperations: ptr.To[int32](3)})
+	if changed == first {
+		t.Error("limiter(...): want a new limiter for changed limits")
+	}
+	if got := limiters.limiter(key, nil); got == changed || got.slots != nil || got.rate != nil {
+		t.Errorf("limiter(...): want a new unlimited limiter without limits, got %+v", got)
+	}
+
+	ForgetLimits(key)
+	if got := limiters.limiter(key, nil); got.slots != nil || got.rate != nil {
+		t.Errorf("limiter(...) after ForgetLimits: want an unlimited limiter, got %+v", got)
+	}
+}
+
+func TestWrapTracksStartedOperations(t *testing.T) {
+	started := make(chan struct{})
+	tr := &tfschema.Resource{
+		Schema: map[string]*tfschema.Schema{"name": {Type: tfschema.TypeString, Optional: true}},
+		ReadContext: func(context.Context, *tfschema.ResourceData, any) diag.Diagnostics {
+			close(started)
+			return nil
+		},
+	}
+	r := &ujconfig.Resource{Name: "opensearch_script", Kind: "Script", TerraformResource: tr}
+	WrapOperations(&ujconfig.Provider{Resources: map[string]*ujconfig.Resource{r.Name: r}})
+
+	key := limitsKey(t)
+	meta := &resourceMeta{
+		key:     key,
+		limiter: limiters.limiter(key, &namespacedv1beta1.LimitsConfig{MaxConcurrentOperations: ptr.To[int32](1)}),
+		managed: &v1alpha1.Script{ObjectMeta: metav1.ObjectMeta{Name: "script", UID: "uid"}},
+	}
+	release, err := meta.limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire(...): %v", err)
+	}
+	done := make(chan diag.Diagnostics)
+	go func() { done <- tr.ReadContext(context.Background(), tr.TestResourceData(), meta) }()
+
+	// An operation that waits for the limits is not in progress, so that it
+	// does not fail the liveness check.
+	stalled := health.InProgress.StallChecker(0)
+	time.Sleep(50 * time.Millisecond)
+	if err := stalled(nil); err != nil {
+		t.Errorf("StallChecker(0)(...) while waiting for the limits: %v", err)
+	}
+	release()
+	<-started
+	if diags := <-done; diags.HasError() {
+		t.Fatalf("ReadContext(...): %v", diags)
+	}
+	if err := stalled(nil); err != nil {
+		t.Errorf("StallChecker(0)(...) once the operation completed: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package clients
+
+import (
+	"net/http"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+)
+
+// A resourceMeta is the meta the operations of the Terraform resources are
+// called with instead of the meta of the Terraform provider. A resourceMeta
+// is created for a managed resource whenever its controller connects. Upjet
+// runs operations asynchronously, in a context that carries nothing of the
+// reconcile that started them, so the resourceMeta carries what the
+// provider needs to know about the managed resource an operation runs for.
+type resourceMeta struct {
+	// provider is the meta of the configured Terraform provider, which the
+	// operations of the Terraform provider are called with.
+	provider any
+
+	// key of the ProviderConfig of the managed resource.
+	key ProviderConfigKey
+
+	// limiter enforces the limits of the ProviderConfig.
+	limiter *operationLimiter
+
+	// managed is a copy of the managed resource, as of the reconcile that
+	// started the operation.
+	managed resource.Managed
+
+	// http is the HTTP client of the connection to the endpoint the
+	// Terraform provider was configured with, whose URL is endpoint.
+	http     *http.Client
+	endpoint string
+}
+
+// providerMetaOf returns the meta of the Terraform provider the supplied
+// meta carries. Any other meta is returned as is.
+func providerMetaOf(meta any) any {
+	if m, ok := meta.(*resourceMeta); ok {
+		return m.provider
+	}
+	return meta
+}
@@ -0,0 +1,107 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	neturl "net/url"
+	"sync"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+const (
+	// tokenExpiryDelta is how long before its expiry a token is renewed,
+	// so that operations started with a token can finish before it
+	// expires.
+	tokenExpiryDelta = 5 * time.Minute
+
+	// tokenSourceIdleTimeout is how long an unused token source is kept.
+	tokenSourceIdleTimeout = time.Hour
+
+	tokenRequestTimeout = 30 * time.Second
+
+	errGetClientSecret = "cannot get OIDC client secret"
+	errGetOIDCToken    = "cannot get OIDC access token"
+)
+
+// A cachedTokenSource is a token source and the time it was last used.
+type cachedTokenSource struct {
+	oauth2.TokenSource
+	lastUsed time.Time
+}
+
+// A tokenSourceCache caches token sources by a digest of everything that
+// determines the tokens they issue, so that tokens are reused across
+// reconciles until they are about to expire.
+type tokenSourceCache struct {
+	mu      sync.Mutex
+	sources map[string]*cachedTokenSource
+}
+
+var tokenSources = &tokenSourceCache{sources: map[string]*cachedTokenSource{}}
+
+// get returns the cached token source with the supplied identity, or the
+// token source returned by newSource. Token sources that were not used
+// within the idle timeout are dropped.
+func (c *tokenSourceCache) get(identity string, newSource func() oauth2.TokenSource) oauth2.TokenSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for id, ts := range c.sources {
+		if now.Sub(ts.lastUsed) > tokenSourceIdleTimeout {
+			delete(c.sources, id)
+		}
+	}
+	ts, ok := c.sources[identity]
+	if !ok {
+		ts = &cachedTokenSource{TokenSource: oauth2.ReuseTokenSourceWithExpiry(nil, newSource(), tokenExpiryDelta)}
+		c.sources[identity] = ts
+	}
+	ts.lastUsed = now
+	return ts
+}
+
+// configureOIDCAuth obtains an access token with the OAuth 2.0 client
+// credentials flow and configures it as bearer token.
+func configureOIDCAuth(ctx context.Context, kube client.Client, a *namespacedv1beta1.OIDCAuth, cfg terraform.ProviderConfiguration) error {
+	secret, err := resource.ExtractSecret(ctx, kube, xpv1.CommonCredentialSelectors{SecretRef: &a.ClientSecretSecretRef})
+	if err != nil {
+		return errors.Wrap(err, errGetClientSecret)
+	}
+	cc := &clientcredentials.Config{
+		ClientID:     a.ClientID,
+		ClientSecret: string(secret),
+		TokenURL:     a.TokenURL,
+		Scopes:       a.Scopes,
+	}
+	if a.Audience != nil {
+		cc.EndpointParams = neturl.Values{"audience": {*a.Audience}}
+	}
+
+	identity, err := digestOf(cc)
+	if err != nil {
+		return err
+	}
+	ts := tokenSources.get(identity, func() oauth2.TokenSource {
+		// The token source outlives the reconcile it was created in.
+		hc := &http.Client{Timeout: tokenRequestTimeout}
+		return cc.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, hc))
+	})
+	t, err := ts.Token()
+	if err != nil {
+		return errors.Wrap(err, errGetOIDCToken)
+	}
+	cfg[token] = t.AccessToken
+	cfg[tokenName] = "Bearer"
+	cfg[tokenSourceSetting] = identity
+	return nil
+}
@@ -0,0 +1,174 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+// withTokenSources replaces the cached token sources for the duration of
+// the test.
+func withTokenSources(t *testing.T) {
+	t.Helper()
+	prev := tokenSources
+	tokenSources = &tokenSourceCache{sources: map[string]*cachedTokenSource{}}
+	t.Cleanup(func() { tokenSources = prev })
+}
+
+// tokenEndpoint returns a token endpoint that issues numbered tokens which
+// expire after the supplied duration, and the number of tokens it issued.
+func tokenEndpoint(t *testing.T, expiresIn time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	issued := &atomic.Int32{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "client_credentials" {
+			http.Error(w, "unsupported grant", http.StatusBadRequest)
+			return
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "provider" || secret == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "token-" + strconv.Itoa(int(n)),
+			"token_type":   "Bearer",
+			"expires_in":   int(expiresIn.Seconds()),
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, issued
+}
+
+func TestConfigureOIDCAuth(t *testing.T) {
+	secret := func(value string) client.Object {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "oidc"},
+			Data:       map[string][]byte{"clientSecret": []byte(value)},
+		}
+	}
+	auth := func(tokenURL string) *namespacedv1beta1.OIDCAuth {
+		return &namespacedv1beta1.OIDCAuth{
+			TokenURL: tokenURL,
+			ClientID: "provider",
+			ClientSecretSecretRef: xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "oidc"},
+				Key:             "clientSecret",
+			},
+		}
+	}
+	configure := func(t *testing.T, kube client.Client, a *namespacedv1beta1.OIDCAuth) terraform.ProviderConfiguration {
+		t.Helper()
+		cfg := terraform.ProviderConfiguration{}
+		if err := configureOIDCAuth(context.Background(), kube, a, cfg); err != nil {
+			t.Fatalf("configureOIDCAuth(...): %v", err)
+		}
+		return cfg
+	}
+
+	t.Run("Reused", func(t *testing.T) {
+		withTokenSources(t)
+		srv, issued := tokenEndpoint(t, time.Hour)
+		kube := fake.NewClientBuilder().WithObjects(secret("s3cr3t")).Build()
+
+		first := configure(t, kube, auth(srv.URL))
+		second := configure(t, kube, auth(srv.URL))
+		if first[token] != "token-1" || second[token] != "token-1" {
+			t.Errorf("tokens: want token-1 twice, got %v and %v", first[token], second[token])
+		}
+		if n := issued.Load(); n != 1 {
+			t.Errorf("issued tokens: want 1, got %d", n)
+		}
+		if first[tokenName] != "Bearer" {
+			t.Errorf("token name: want Bearer, got %v", first[tokenName])
+		}
+		if first[tokenSourceSetting] == "" || first[tokenSourceSetting] != second[tokenSourceSetting] {
+			t.Errorf("token sources: want the same, got %v and %v", first[tokenSourceSetting], second[tokenSourceSetting])
+		}
+	})
+
+	t.Run("RenewedBeforeExpiry", func(t *testing.T) {
+		withTokenSources(t)
+		// Tokens that expire within the expiry delta are renewed on use.
+		srv, issued := tokenEndpoint(t, tokenExpiryDelta/2)
+		kube := fake.NewClientBuilder().WithObjects(secret("s3cr3t")).Build()
+
+		first := configure(t, kube, auth(srv.URL))
+		second := configure(t, kube, auth(srv.URL))
+		if first[token] != "token-1" || second[token] != "token-2" {
+			t.Errorf("tokens: want token-1 and token-2, got %v and %v", first[token], second[token])
+		}
+		if n := issued.Load(); n != 2 {
+			t.Errorf("issued tokens: want 2, got %d", n)
+		}
+		if first[tokenSourceSetting] != second[tokenSourceSetting] {
+			t.Error("renewing a token changed its token source")
+		}
+	})
+
+	t.Run("RotatedClientSecret", func(t *testing.T) {
+		withTokenSources(t)
+		srv, issued := tokenEndpoint(t, time.Hour)
+
+		first := configure(t, fake.NewClientBuilder().WithObjects(secret("s3cr3t")).Build(), auth(srv.URL))
+		second := configure(t, fake.NewClientBuilder().WithObjects(secret("rotated")).Build(), auth(srv.URL))
+		if first[token] == second[token] {
+			t.Errorf("token %v was reused with a rotated client secret", first[token])
+		}
+		if n := issued.Load(); n != 2 {
+			t.Errorf("issued tokens: want 2, got %d", n)
+		}
+		if first[tokenSourceSetting] == second[tokenSourceSetting] {
+			t.Error("rotating the client secret did not change the token source")
+		}
+	})
+
+	t.Run("TokenEndpointError", func(t *testing.T) {
+		withTokenSources(t)
+		srv, _ := tokenEndpoint(t, time.Hour)
+		a := auth(srv.URL)
+		a.ClientID = "unknown"
+		kube := fake.NewClientBuilder().WithObjects(secret("s3cr3t")).Build()
+		if err := configureOIDCAuth(context.Background(), kube, a, terraform.ProviderConfiguration{}); err == nil {
+			t.Error("configureOIDCAuth(...): want error, got nil")
+		}
+	})
+}
+
+func TestTokenSourceCacheIdle(t *testing.T) {
+	c := &tokenSourceCache{sources: map[string]*cachedTokenSource{}}
+	created := 0
+	newSource := func() oauth2.TokenSource {
+		created++
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	}
+	c.get("a", newSource)
+	c.get("a", newSource)
+	if created != 1 {
+		t.Fatalf("created token sources: want 1, got %d", created)
+	}
+	c.sources["a"].lastUsed = time.Now().Add(-tokenSourceIdleTimeout - time.Minute)
+	c.get("b", newSource)
+	if _, ok := c.sources["a"]; ok {
+		t.Error("idle token source was not dropped")
+	}
+	if created != 2 {
+		t.Errorf("created token sources: want 2, got %d", created)
+	}
+}
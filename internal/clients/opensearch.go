@@ -2,17 +2,27 @@ package clients
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"path/filepath"
+	"strings"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/upjet/v2/pkg/terraform"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	clusterv1beta1 "github.com/tagesjump/provider-opensearch/apis/cluster/v1beta1"
 	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+	"github.com/tagesjump/provider-opensearch/internal/logs"
 )
 
 const (
@@ -77,11 +87,68 @@ const (
 	errExtractCredentials   = "cannot extract credentials"
 	errUnmarshalCredentials = "cannot unmarshal opensearch credentials as JSON"
 	errNoRequiredFieldURL   = "Missing required field - url"
+	errConfigureTLS         = "cannot configure TLS"
 )
 
+// ProviderConfigKey identifies a ProviderConfig or ClusterProviderConfig of
+// either API scope.
+type ProviderConfigKey struct {
+	schema.GroupKind
+
+	// Namespace of the ProviderConfig, empty for cluster-scoped kinds.
+	Namespace string
+
+	// Name of the ProviderConfig.
+	Name string
+}
+
+// String returns the kind, namespace and name of the ProviderConfig, e.g.
+// ProviderConfig.opensearch.m.upbound.io/team-search/default.
+func (k ProviderConfigKey) String() string {
+	if k.Namespace == "" {
+		return k.GroupKind.String() + "/" + k.Name
+	}
+	return k.GroupKind.String() + "/" + k.Namespace + "/" + k.Name
+}
+
+// dir returns the directory below root that is private to the
+// ProviderConfig.
+func (k ProviderConfigKey) dir(root string) string {
+	return filepath.Join(root, strings.ToLower(k.GroupKind.String()), k.Namespace, k.Name)
+}
+
+type setupBuilder struct {
+	cli bool
+}
+
+// A SetupOption configures the terraform.SetupFn TerraformSetupBuilder
+// builds.
+type SetupOption func(*setupBuilder)
+
+// WithTerraformCLI builds a terraform.SetupFn for managed resources that are
+// reconciled with the Terraform CLI instead of the in-process Terraform
+// provider. The in-process provider is not configured for them, and their
+// operations do not go through WrapOperations, so that ProviderConfigs with
+// limits are rejected rather than silently not limited.
+func WithTerraformCLI() SetupOption {
+	return func(b *setupBuilder) {
+		b.cli = true
+	}
+}
+
 // TerraformSetupBuilder builds Terraform a terraform.SetupFn function which
-// returns Terraform provider setup configuration
-func TerraformSetupBuilder(version, providerSource, providerVersion string) terraform.SetupFn {
+// returns Terraform provider setup configuration. The supplied Terraform
+// provider is configured once per ProviderConfig and configuration, and its
+// meta is reused until the configuration changes. The operations of
+// Terraform resources are called with a meta per managed resource, which
+// WrapOperations unwraps for the Terraform provider. The supplied scheduler, if
+// any, runs the native provider processes of resources that are reconciled
+// with the Terraform CLI.
+func TerraformSetupBuilder(version, providerSource, providerVersion string, tfProvider *tfschema.Provider, scheduler terraform.ProviderScheduler, opts ...SetupOption) terraform.SetupFn {
+	b := &setupBuilder{}
+	for _, fn := range opts {
+		fn(b)
+	}
 	return func(ctx context.Context, client client.Client, mg resource.Managed) (terraform.Setup, error) {
 		ps := terraform.Setup{
 			Version: version,
@@ -89,43 +156,134 @@ func TerraformSetupBuilder(version, providerSource, providerVersion string) terr
 				Source:  providerSource,
 				Version: providerVersion,
 			},
+			Scheduler: scheduler,
 		}
 
-		pcSpec, err := resolveProviderConfig(ctx, client, mg)
+		pcKey, pcSpec, err := resolveProviderConfig(ctx, client, mg)
 		if err != nil {
 			return terraform.Setup{}, errors.Wrap(err, "cannot resolve provider config")
 		}
+		logs.SetProviderConfig(mg.GetUID(), pcKey.String())
+		if b.cli && pcSpec.Limits != nil {
+			return terraform.Setup{}, errors.Errorf(errCLIUnsupported, "spec.limits of "+pcKey.String())
+		}
 
-		data, err := resource.CommonCredentialExtractor(ctx, pcSpec.Credentials.Source, client, pcSpec.Credentials.CommonCredentialSelectors)
+		cfg, err := Configuration(ctx, client, pcKey, pcSpec)
 		if err != nil {
-			return ps, errors.Wrap(err, errExtractCredentials)
+			return ps, err
 		}
-		creds := map[string]string{}
-		if err := json.Unmarshal(data, &creds); err != nil {
-			return ps, errors.Wrap(err, errUnmarshalCredentials)
+		cfg = endpoints.selectEndpoint(pcKey, cfg)
+		ps.Configuration = terraformSettings(cfg)
+		if b.cli {
+			// The Terraform CLI configures the native provider itself.
+			return ps, nil
 		}
 
-		// set provider configuration
-		ps.Configuration = map[string]any{}
-
-		if value, ok := creds[url]; ok {
-			ps.Configuration[url] = value
-		} else {
-			return ps, errors.New(errNoRequiredFieldURL)
+		conn, err := connections.get(pcKey, cfg)
+		if err != nil {
+			return ps, err
+		}
+		meta, err := conn.providerMeta(ctx, tfProvider, cfg)
+		if err != nil {
+			return ps, err
+		}
+		hc, err := conn.httpClient(cfg)
+		if err != nil {
+			return ps, err
 		}
+		ps.Meta = &resourceMeta{
+			provider: meta,
+			key:      pcKey,
+			limiter:  limiters.limiter(pcKey, pcSpec.Limits),
+			managed:  mg.DeepCopyObject().(resource.Managed),
+			http:     hc,
+			endpoint: stringSetting(cfg, url),
+		}
+		return ps, nil
+	}
+}
 
-		for _, setting := range []string{
-			awsAccessKey, awsAssumeRoleArn, awsAssumeRoleExternalId, awsProfile, awsRegion,
-			awsSecretKey, awsSignatureService, awsToken, cacertFile, clientCertPath, clientKeyPath,
-			healthcheck, hostOverride, insecure, opensearchVersion, password, proxy, signAwsRequests,
-			sniff, token, tokenName, username, versionPingTimeout,
-		} {
-			if value, ok := creds[setting]; ok {
-				ps.Configuration[setting] = value
-			}
+// Configuration resolves the Terraform provider configuration of the
+// supplied ProviderConfig spec.
+func Configuration(ctx context.Context, kube client.Client, key ProviderConfigKey, spec *namespacedv1beta1.ProviderConfigSpec) (terraform.ProviderConfiguration, error) {
+	cfg, err := credentialsConfiguration(ctx, kube, spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := configureFromSpec(ctx, kube, spec, cfg); err != nil {
+		return nil, errors.Wrap(err, errConfigureFromSpec)
+	}
+	if _, ok := cfg[url]; !ok {
+		return nil, errors.New(errNoRequiredFieldURL)
+	}
+	if err := checkInjectedIdentity(spec, cfg); err != nil {
+		return nil, err
+	}
+	if err := configureTLS(ctx, kube, key, spec, cfg); err != nil {
+		return nil, errors.Wrap(err, errConfigureTLS)
+	}
+	return cfg, nil
+}
+
+// ConfigurationDigest returns a digest of the supplied provider
+// configuration that changes whenever any of its settings changes. Tokens
+// the provider obtains itself are represented by their source, so that
+// renewing them does not change the digest. Digests are only comparable
+// within the process.
+func ConfigurationDigest(cfg terraform.ProviderConfiguration) (string, error) {
+	if _, ok := cfg[tokenSourceSetting]; ok {
+		stable := make(terraform.ProviderConfiguration, len(cfg))
+		for k, v := range cfg {
+			stable[k] = v
 		}
+		delete(stable, token)
+		cfg = stable
+	}
+	return digestOf(cfg)
+}
 
-		return ps, nil
+// digestKey keys the digests of digestOf. Digests cover credentials, and
+// may end up in the annotations of managed resources, so that they must
+// not be comparable across processes.
+var digestKey = func() []byte {
+	k := make([]byte, sha256.Size)
+	if _, err := rand.Read(k); err != nil {
+		panic(err)
+	}
+	return k
+}()
+
+// digestOf returns a digest of the JSON representation of the supplied
+// value, which is an HMAC keyed with random data of the process.
+func digestOf(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, digestKey)
+	_, _ = mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// ResolveProviderConfig returns the key and the shared spec of the supplied
+// ProviderConfig or ClusterProviderConfig of either API scope.
+func ResolveProviderConfig(obj client.Object) (ProviderConfigKey, *namespacedv1beta1.ProviderConfigSpec, error) {
+	key := ProviderConfigKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	switch pc := obj.(type) {
+	case *clusterv1beta1.ProviderConfig:
+		key.GroupKind = clusterv1beta1.ProviderConfigGroupVersionKind.GroupKind()
+		spec, err := toSharedPCSpec(pc)
+		return key, spec, err
+	case *namespacedv1beta1.ProviderConfig:
+		key.GroupKind = namespacedv1beta1.ProviderConfigGroupVersionKind.GroupKind()
+		spec := pc.Spec.DeepCopy()
+		localizeSecretRefs(spec, pc.GetNamespace())
+		return key, spec, nil
+	case *namespacedv1beta1.ClusterProviderConfig:
+		key.GroupKind = namespacedv1beta1.ClusterProviderConfigGroupVersionKind.GroupKind()
+		return key, pc.Spec.DeepCopy(), nil
+	default:
+		return ProviderConfigKey{}, nil, errors.New("unknown provider config type")
 	}
 }
 
@@ -143,72 +301,116 @@ func toSharedPCSpec(pc *clusterv1beta1.ProviderConfig) (*namespacedv1beta1.Provi
 	return &mSpec, err
 }
 
-func resolveProviderConfig(ctx context.Context, crClient client.Client, mg resource.Managed) (*namespacedv1beta1.ProviderConfigSpec, error) {
+func resolveProviderConfig(ctx context.Context, crClient client.Client, mg resource.Managed) (ProviderConfigKey, *namespacedv1beta1.ProviderConfigSpec, error) {
 	switch managed := mg.(type) {
 	case resource.LegacyManaged:
 		return resolveLegacy(ctx, crClient, managed)
 	case resource.ModernManaged:
 		return resolveModern(ctx, crClient, managed)
 	default:
-		return nil, errors.New("resource is not a managed resource")
+		return ProviderConfigKey{}, nil, errors.New("resource is not a managed resource")
 	}
 }
 
-func resolveLegacy(ctx context.Context, client client.Client, mg resource.LegacyManaged) (*namespacedv1beta1.ProviderConfigSpec, error) {
+func resolveLegacy(ctx context.Context, client client.Client, mg resource.LegacyManaged) (ProviderConfigKey, *namespacedv1beta1.ProviderConfigSpec, error) {
 	configRef := mg.GetProviderConfigReference()
 	if configRef == nil {
-		return nil, errors.New(errNoProviderConfig)
+		return ProviderConfigKey{}, nil, errors.New(errNoProviderConfig)
 	}
 	pc := &clusterv1beta1.ProviderConfig{}
 	if err := client.Get(ctx, types.NamespacedName{Name: configRef.Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetProviderConfig)
+		return ProviderConfigKey{}, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
 	t := resource.NewLegacyProviderConfigUsageTracker(client, &clusterv1beta1.ProviderConfigUsage{})
 	if err := t.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackUsage)
+		return ProviderConfigKey{}, nil, errors.Wrap(err, errTrackUsage)
 	}
 
-	return toSharedPCSpec(pc)
+	return ResolveProviderConfig(pc)
 }
 
-func resolveModern(ctx context.Context, crClient client.Client, mg resource.ModernManaged) (*namespacedv1beta1.ProviderConfigSpec, error) {
+func resolveModern(ctx context.Context, crClient client.Client, mg resource.ModernManaged) (ProviderConfigKey, *namespacedv1beta1.ProviderConfigSpec, error) {
 	configRef := mg.GetProviderConfigReference()
 	if configRef == nil {
-		return nil, errors.New(errNoProviderConfig)
+		return ProviderConfigKey{}, nil, errors.New(errNoProviderConfig)
 	}
 
 	pcRuntimeObj, err := crClient.Scheme().New(namespacedv1beta1.SchemeGroupVersion.WithKind(configRef.Kind))
 	if err != nil {
-		return nil, errors.Wrap(err, "unknown GVK for ProviderConfig")
+		return ProviderConfigKey{}, nil, errors.Wrap(err, "unknown GVK for ProviderConfig")
 	}
 	pcObj, ok := pcRuntimeObj.(client.Object)
 	if !ok {
 		// This indicates a programming error, types are not properly generated
-		return nil, errors.New(" is not an Object")
+		return ProviderConfigKey{}, nil, errors.New(" is not an Object")
 	}
 
 	// Namespace will be ignored if the PC is a cluster-scoped type
 	if err := crClient.Get(ctx, types.NamespacedName{Name: configRef.Name, Namespace: mg.GetNamespace()}, pcObj); err != nil {
-		return nil, errors.Wrap(err, errGetProviderConfig)
+		return ProviderConfigKey{}, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
-	var pcSpec namespacedv1beta1.ProviderConfigSpec
-	pcu := &namespacedv1beta1.ProviderConfigUsage{}
-	switch pc := pcObj.(type) {
-	case *namespacedv1beta1.ProviderConfig:
-		pcSpec = pc.Spec
-		if pcSpec.Credentials.SecretRef != nil {
-			pcSpec.Credentials.SecretRef.Namespace = mg.GetNamespace()
+	t := resource.NewProviderConfigUsageTracker(crClient, &namespacedv1beta1.ProviderConfigUsage{})
+	if err := t.Track(ctx, mg); err != nil {
+		return ProviderConfigKey{}, nil, errors.Wrap(err, errTrackUsage)
+	}
+	return ResolveProviderConfig(pcObj)
+}
+
+// SecretRefs returns the namespaced names of all Secrets the supplied spec
+// references.
+func SecretRefs(spec *namespacedv1beta1.ProviderConfigSpec) []types.NamespacedName {
+	var refs []types.NamespacedName
+	if ref := spec.Credentials.SecretRef; ref != nil && spec.Credentials.Source == xpv1.CredentialsSourceSecret {
+		refs = append(refs, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name})
+	}
+	for _, ref := range secretKeyRefs(spec) {
+		refs = append(refs, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name})
+	}
+	return refs
+}
+
+// localizeSecretRefs points every Secret and ServiceAccount reference of a
+// namespaced ProviderConfig to the namespace of that ProviderConfig, which
+// is the only namespace it may use Secrets and ServiceAccounts from.
+func localizeSecretRefs(spec *namespacedv1beta1.ProviderConfigSpec, namespace string) {
+	if spec.Credentials.SecretRef != nil {
+		spec.Credentials.SecretRef.Namespace = namespace
+	}
+	for _, ref := range secretKeyRefs(spec) {
+		ref.Namespace = namespace
+	}
+	if a := spec.Auth; a != nil && a.ServiceAccountToken != nil {
+		a.ServiceAccountToken.ServiceAccountRef.Namespace = namespace
+	}
+}
+
+// secretKeyRefs returns all Secret key references of the supplied spec.
+func secretKeyRefs(spec *namespacedv1beta1.ProviderConfigSpec) []*xpv1.SecretKeySelector {
+	var refs []*xpv1.SecretKeySelector
+	if t := spec.TLS; t != nil {
+		refs = append(refs, t.CACertSecretRef, t.ClientCertSecretRef, t.ClientKeySecretRef)
+	}
+	if a := spec.Auth; a != nil {
+		if a.Basic != nil {
+			refs = append(refs, &a.Basic.PasswordSecretRef)
+		}
+		if a.Token != nil {
+			refs = append(refs, &a.Token.TokenSecretRef)
+		}
+		if a.AWS != nil {
+			refs = append(refs, a.AWS.AccessKeyIDSecretRef, a.AWS.SecretAccessKeySecretRef, a.AWS.SessionTokenSecretRef)
+		}
+		if a.OIDC != nil {
+			refs = append(refs, &a.OIDC.ClientSecretSecretRef)
 		}
-	case *namespacedv1beta1.ClusterProviderConfig:
-		pcSpec = pc.Spec
-	default:
-		return nil, errors.New("unknown provider config type")
 	}
-	t := resource.NewProviderConfigUsageTracker(crClient, pcu)
-	if err := t.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackUsage)
+	result := refs[:0]
+	for _, ref := range refs {
+		if ref != nil {
+			result = append(result, ref)
+		}
 	}
-	return &pcSpec, nil
+	return result
 }
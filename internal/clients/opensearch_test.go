@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apisCluster "github.com/tagesjump/provider-opensearch/apis/cluster"
+	"github.com/tagesjump/provider-opensearch/apis/cluster/opensearch/v1alpha1"
+	clusterv1beta1 "github.com/tagesjump/provider-opensearch/apis/cluster/v1beta1"
+)
+
+func TestConfigurationDigest(t *testing.T) {
+	digest := func(cfg terraform.ProviderConfiguration) string {
+		t.Helper()
+		d, err := ConfigurationDigest(cfg)
+		if err != nil {
+			t.Fatalf("ConfigurationDigest(...): %v", err)
+		}
+		return d
+	}
+	basic := terraform.ProviderConfiguration{url: "https://opensearch:9200", username: "admin", password: "secret"}
+	rotated := terraform.ProviderConfiguration{url: "https://opensearch:9200", username: "admin", password: "rotated"}
+	if digest(basic) != digest(basic) {
+		t.Error("digests of the same configuration differ")
+	}
+	if digest(basic) == digest(rotated) {
+		t.Error("digest did not change with the password")
+	}
+
+	// Unkeyed digests of credentials could be brute-forced.
+	data, _ := json.Marshal(basic)
+	sum := sha256.Sum256(data)
+	if d := digest(basic); d == hex.EncodeToString(sum[:]) {
+		t.Errorf("digest %s is the unkeyed SHA-256 of the configuration", d)
+	}
+
+	// Renewed tokens do not change the digest.
+	first := terraform.ProviderConfiguration{url: "https://opensearch:9200", tokenSourceSetting: "source", token: "first"}
+	renewed := terraform.ProviderConfiguration{url: "https://opensearch:9200", tokenSourceSetting: "source", token: "renewed"}
+	if digest(first) != digest(renewed) {
+		t.Error("digest changed with a renewed token")
+	}
+}
+
+func TestTerraformSetupBuilderCLI(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apisCluster.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme(...): %v", err)
+	}
+	pc := func(name string, limits *clusterv1beta1.LimitsConfig) *clusterv1beta1.ProviderConfig {
+		return &clusterv1beta1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: clusterv1beta1.ProviderConfigSpec{
+				Credentials: clusterv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceNone},
+				URL:         ptr.To("http://opensearch.test:9200"),
+				Limits:      limits,
+			},
+		}
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pc("limited", &clusterv1beta1.LimitsConfig{MaxConcurrentOperations: ptr.To[int32](1)}),
+		pc("unlimited", nil),
+	).Build()
+	tfProvider := &tfschema.Provider{
+		Schema: map[string]*tfschema.Schema{url: {Type: tfschema.TypeString, Optional: true}},
+		ConfigureContextFunc: func(context.Context, *tfschema.ResourceData) (any, diag.Diagnostics) {
+			t.Error("the in-process Terraform provider was configured for the Terraform CLI")
+			return nil, nil
+		},
+	}
+	setup := TerraformSetupBuilder("", "", "", tfProvider, nil, WithTerraformCLI())
+	connect := func(pc string) error {
+		mg := &v1alpha1.Script{ObjectMeta: metav1.ObjectMeta{Name: "script-" + pc, UID: types.UID("uid-" + pc)}}
+		mg.SetProviderConfigReference(&xpv1.Reference{Name: pc})
+		_, err := setup(context.Background(), kube, mg)
+		return err
+	}
+
+	if err := connect("limited"); err == nil || !strings.Contains(err.Error(), "spec.limits") {
+		t.Errorf("setup with limits: want an error about spec.limits, got %v", err)
+	}
+	if err := connect("unlimited"); err != nil {
+		t.Errorf("setup without limits: %v", err)
+	}
+}
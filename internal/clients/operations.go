@@ -0,0 +1,270 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/apis/changelogs/proto/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	elastic7 "github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tagesjump/provider-opensearch/internal/changelogs"
+	"github.com/tagesjump/provider-opensearch/internal/health"
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+)
+
+// Operations of Terraform resources.
+const (
+	operationCreate = "create"
+	operationRead   = "read"
+	operationUpdate = "update"
+	operationDelete = "delete"
+)
+
+// Operations that change resources, and the types of their change log
+// entries.
+var changeLogOperations = map[string]v1alpha1.OperationType{
+	operationCreate: v1alpha1.OperationType_OPERATION_TYPE_CREATE,
+	operationUpdate: v1alpha1.OperationType_OPERATION_TYPE_UPDATE,
+	operationDelete: v1alpha1.OperationType_OPERATION_TYPE_DELETE,
+}
+
+// operations configures how the operations of Terraform resources are
+// wrapped.
+type operations struct {
+	changeLogger managed.ChangeLogger
+	log          logging.Logger
+	applied      *appliedStates
+}
+
+// An OperationsOption configures how WrapOperations wraps operations.
+type OperationsOption func(*operations)
+
+// WithChangeLogger records a change log entry with the supplied
+// ChangeLogger for every create, update and delete operation once it
+// completed.
+func WithChangeLogger(cl managed.ChangeLogger) OperationsOption {
+	return func(o *operations) {
+		o.changeLogger = cl
+	}
+}
+
+// WithLogger configures the logger that wrapped operations log with.
+func WithLogger(l logging.Logger) OperationsOption {
+	return func(o *operations) {
+		o.log = l
+	}
+}
+
+// WrapOperations wraps the create, read, update and delete operations of
+// the Terraform resources of the supplied provider, so that they wait for
+// the limits of the ProviderConfig they run for and are recorded in the
+// metrics and change logs of the provider. The operations of the Terraform
+// provider are called with the meta of the Terraform provider instead of
+// the meta of the managed resource they run for, and with a context, even
+// if they do not take one. Resources with attributes the Terraform provider
+// does not support are extended with operations that are called with the
+// meta of the managed resource. It must be called before the provider is
+// used.
+func WrapOperations(p *ujconfig.Provider, opts ...OperationsOption) {
+	o := &operations{log: logging.NewNopLogger(), applied: newAppliedStates()}
+	for _, fn := range opts {
+		fn(o)
+	}
+	for _, r := range p.Resources {
+		tr := r.TerraformResource
+		if tr == nil {
+			continue
+		}
+		tr.CreateContext, tr.Create = unwrapMeta(tr.CreateContext, tr.Create), nil
+		tr.ReadContext, tr.Read = unwrapMeta(tr.ReadContext, tr.Read), nil
+		tr.UpdateContext, tr.Update = unwrapMeta(tr.UpdateContext, tr.Update), nil
+		tr.DeleteContext, tr.Delete = unwrapMeta(tr.DeleteContext, tr.Delete), nil
+		tr.CreateWithoutTimeout = unwrapMeta(tr.CreateWithoutTimeout, nil)
+		tr.ReadWithoutTimeout = unwrapMeta(tr.ReadWithoutTimeout, nil)
+		tr.UpdateWithoutTimeout = unwrapMeta(tr.UpdateWithoutTimeout, nil)
+		tr.DeleteWithoutTimeout = unwrapMeta(tr.DeleteWithoutTimeout, nil)
+		if extend, ok := extensions[r.Name]; ok {
+			extend(tr)
+		}
+		tr.CreateContext = wrap(o, tr.CreateContext, r, operationCreate)
+		tr.ReadContext = wrap(o, tr.ReadContext, r, operationRead)
+		tr.UpdateContext = wrap(o, tr.UpdateContext, r, operationUpdate)
+		tr.DeleteContext = wrap(o, tr.DeleteContext, r, operationDelete)
+		tr.CreateWithoutTimeout = wrap(o, tr.CreateWithoutTimeout, r, operationCreate)
+		tr.ReadWithoutTimeout = wrap(o, tr.ReadWithoutTimeout, r, operationRead)
+		tr.UpdateWithoutTimeout = wrap(o, tr.UpdateWithoutTimeout, r, operationUpdate)
+		tr.DeleteWithoutTimeout = wrap(o, tr.DeleteWithoutTimeout, r, operationDelete)
+		if cd := tr.CustomizeDiff; cd != nil {
+			tr.CustomizeDiff = func(ctx context.Context, d *tfschema.ResourceDiff, meta any) error {
+				return cd(ctx, d, providerMetaOf(meta))
+			}
+		}
+	}
+}
+
+// unwrapMeta returns the supplied operation, or the supplied legacy
+// operation without context, called with the meta of the Terraform
+// provider. It returns nil if both are nil.
+func unwrapMeta[F ~func(context.Context, *tfschema.ResourceData, any) diag.Diagnostics](op F, legacy func(*tfschema.ResourceData, any) error) F {
+	switch {
+	case op != nil:
+		return func(ctx context.Context, d *tfschema.ResourceData, meta any) diag.Diagnostics {
+			return op(ctx, d, providerMetaOf(meta))
+		}
+	case legacy != nil:
+		return func(_ context.Context, d *tfschema.ResourceData, meta any) diag.Diagnostics {
+			return diag.FromErr(legacy(d, providerMetaOf(meta)))
+		}
+	}
+	return nil
+}
+
+// wrap returns the supplied operation wrapped so that it waits for the
+// limits of its ProviderConfig, is tracked while in progress and is
+// recorded in the metrics and the health of the endpoint it used. Updates
+// are counted as drift if they restore the state last applied. Once an
+// operation that changes a resource of the supplied resource completed, its
+// change log entry is recorded. It returns nil if the operation is nil.
+func wrap[F ~func(context.Context, *tfschema.ResourceData, any) diag.Diagnostics](o *operations, op F, r *ujconfig.Resource, operation string) F {
+	if op == nil {
+		return nil
+	}
+	kind := r.Kind
+	return func(ctx context.Context, d *tfschema.ResourceData, meta any) diag.Diagnostics {
+		what := operation + " of " + kind + " " + d.Id()
+		m, ok := meta.(*resourceMeta)
+		if !ok {
+			// Operations that do not run for a managed resource are
+			// neither limited nor recorded.
+			defer health.InProgress.Start(what)()
+			return op(ctx, d, meta)
+		}
+		release, err := m.limiter.acquire(ctx)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer release()
+		// Operations are only tracked once they started, so that waiting
+		// for the limits does not count as a stall.
+		defer health.InProgress.Start(what)()
+
+		// Whether an update corrects drift is only known before it
+		// applied the desired state.
+		uid := m.managed.GetUID()
+		drifted := operation == operationUpdate && o.applied.drifted(uid, r.TerraformResource, d)
+
+		start := time.Now()
+		diags := op(withKind(ctx, kind), d, m)
+		err = diagsError(diags)
+		observe(m.key, kind, operation, statusCode(err), time.Since(start))
+		// The Terraform provider sends its requests with its own clients,
+		// so that only the outcome of its operations is known.
+		switch {
+		case err == nil:
+			endpoints.recordRequest(m.key, m.endpoint, nil)
+		case endpointFailed(err):
+			endpoints.recordRequest(m.key, m.endpoint, err)
+		}
+		if drifted {
+			metrics.DriftDetected.With(prometheus.Labels{"provider_config": m.key.String(), "kind": kind}).Inc()
+		}
+		if err == nil {
+			switch operation {
+			case operationCreate, operationUpdate:
+				o.applied.record(uid, r.TerraformResource, d)
+			case operationDelete:
+				o.applied.forget(uid)
+			}
+		}
+		if t, ok := changeLogOperations[operation]; ok && o.changeLogger != nil {
+			ad := changelogs.Details(r, d, operation == operationDelete && err == nil)
+			if drifted {
+				ad[changelogs.DetailDrift] = "true"
+			}
+			if lErr := o.changeLogger.Log(ctx, m.managed, t, err, ad); lErr != nil {
+				o.log.Info("Cannot record change log entry", "kind", kind, "name", m.managed.GetName(), "error", lErr)
+			}
+		}
+		return diags
+	}
+}
+
+// diagsError returns an error with the summaries of the errors of the
+// supplied diagnostics, or nil if there are none.
+func diagsError(diags diag.Diagnostics) error {
+	var msgs []string
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			msgs = append(msgs, d.Summary)
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// observe records an operation for the supplied ProviderConfig that failed
+// with the supplied code, if it is not empty, in the metrics.
+func observe(key ProviderConfigKey, kind, operation, code string, d time.Duration) {
+	pc := key.String()
+	result := "success"
+	if code != "" {
+		result = "failure"
+	}
+	metrics.Operations.With(prometheus.Labels{"operation": operation, "result": result, "code": code, "provider_config": pc, "kind": kind}).Inc()
+	metrics.OperationDuration.With(prometheus.Labels{"operation": operation, "result": result, "provider_config": pc, "kind": kind}).Observe(d.Seconds())
+}
+
+// elasticStatus matches the HTTP status code in the message of errors of
+// the OpenSearch client of the Terraform provider. The Terraform provider
+// mostly wraps these errors with %s, and diagnostics only carry messages,
+// so that the status code can only be parsed from the message.
+var elasticStatus = regexp.MustCompile(`elastic: Error (\d{3}) `)
+
+// connectionError matches the messages of errors of requests that could not
+// be sent to or were not answered by an endpoint.
+var connectionError = regexp.MustCompile(`no available connection|no Elasticsearch node available|dial tcp|connection refused|connection reset|i/o timeout|Client\.Timeout exceeded|tls: `)
+
+// endpointFailed returns true if the supplied error of an operation means
+// that the endpoint it used failed, i.e. that it could not be reached or
+// answered with a server error. Other errors, e.g. invalid requests, say
+// nothing about the endpoint.
+func endpointFailed(err error) bool {
+	if code, convErr := strconv.Atoi(statusCode(err)); convErr == nil {
+		return code >= http.StatusInternalServerError
+	}
+	return connectionError.MatchString(err.Error())
+}
+
+// statusCode returns the HTTP status code OpenSearch answered the request
+// that caused the supplied error with, "error" if it is not known, or an
+// empty string if there is no error.
+func statusCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	var e *elastic7.Error
+	if errors.As(err, &e) && e.Status != 0 {
+		return strconv.Itoa(e.Status)
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return strconv.Itoa(se.StatusCode)
+	}
+	if m := elasticStatus.FindStringSubmatch(err.Error()); m != nil {
+		return m[1]
+	}
+	return "error"
+}
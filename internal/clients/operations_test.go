@@ -0,0 +1,348 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	changelogsv1alpha1 "github.com/crossplane/crossplane-runtime/v2/apis/changelogs/proto/v1alpha1"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	tjcontroller "github.com/crossplane/upjet/v2/pkg/controller"
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	elastic7 "github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apisCluster "github.com/tagesjump/provider-opensearch/apis/cluster"
+	"github.com/tagesjump/provider-opensearch/apis/cluster/opensearch/v1alpha1"
+	clusterv1beta1 "github.com/tagesjump/provider-opensearch/apis/cluster/v1beta1"
+	"github.com/tagesjump/provider-opensearch/config"
+	"github.com/tagesjump/provider-opensearch/internal/changelogs"
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+)
+
+// An entry is a change log entry recorded by a changeLogger.
+type entry struct {
+	name string
+	op   changelogsv1alpha1.OperationType
+	err  error
+	ad   managed.AdditionalDetails
+}
+
+// A changeLogger records the change log entries it is asked to log.
+type changeLogger struct {
+	mu      sync.Mutex
+	entries []entry
+}
+
+func (c *changeLogger) Log(_ context.Context, mg resource.Managed, op changelogsv1alpha1.OperationType, err error, ad managed.AdditionalDetails) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = append(c.entries, entry{name: mg.GetName(), op: op, err: err, ad: ad})
+	return nil
+}
+
+func (c *changeLogger) last() (entry, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) == 0 {
+		return entry{}, 0
+	}
+	return c.entries[len(c.entries)-1], len(c.entries)
+}
+
+// A callbacks signals the completion of async operations.
+type callbacks chan error
+
+func (c callbacks) fn(err error, _ context.Context) error {
+	c <- err
+	return nil
+}
+
+func (c callbacks) Create(types.NamespacedName) terraform.CallbackFn  { return c.fn }
+func (c callbacks) Update(types.NamespacedName) terraform.CallbackFn  { return c.fn }
+func (c callbacks) Destroy(types.NamespacedName) terraform.CallbackFn { return c.fn }
+
+func (c callbacks) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c:
+	case <-time.After(30 * time.Second):
+		t.Fatal("async operation did not complete")
+	}
+}
+
+// scripts fakes the stored scripts of OpenSearch.
+type scripts struct {
+	mu      sync.Mutex
+	sources map[string]string
+	failing bool
+}
+
+func (s *scripts) fail(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func (s *scripts) set(id, source string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sources[id] = source
+}
+
+func (s *scripts) resource(schema map[string]*tfschema.Schema) *tfschema.Resource {
+	return &tfschema.Resource{
+		Schema: schema,
+		CreateContext: func(_ context.Context, d *tfschema.ResourceData, meta any) diag.Diagnostics {
+			if meta != "provider meta" {
+				return diag.Errorf("unexpected meta %v", meta)
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			id := d.Get("script_id").(string)
+			s.sources[id] = d.Get("source").(string)
+			d.SetId(id)
+			return nil
+		},
+		ReadContext: func(_ context.Context, d *tfschema.ResourceData, _ any) diag.Diagnostics {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			src, ok := s.sources[d.Id()]
+			if !ok {
+				d.SetId("")
+				return nil
+			}
+			_ = d.Set("script_id", d.Id())
+			_ = d.Set("source", src)
+			return nil
+		},
+		UpdateContext: func(_ context.Context, d *tfschema.ResourceData, _ any) diag.Diagnostics {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.failing {
+				return diag.Errorf("elastic: Error 409 (Conflict)")
+			}
+			s.sources[d.Id()] = d.Get("source").(string)
+			return nil
+		},
+		DeleteContext: func(_ context.Context, d *tfschema.ResourceData, _ any) diag.Diagnostics {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.sources, d.Id())
+			return nil
+		},
+	}
+}
+
+func TestWrapOperationsAsyncChangeLogs(t *testing.T) {
+	ctx := context.Background()
+
+	pc, err := config.GetProvider(false)
+	if err != nil {
+		t.Fatalf("GetProvider(...): %v", err)
+	}
+	r := pc.Resources["opensearch_script"]
+	s := &scripts{sources: map[string]string{}}
+	r.TerraformResource = s.resource(r.TerraformResource.Schema)
+	cl := &changeLogger{}
+	WrapOperations(&ujconfig.Provider{Resources: map[string]*ujconfig.Resource{r.Name: r}}, WithChangeLogger(cl))
+
+	scheme := runtime.NewScheme()
+	if err := apisCluster.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme(...): %v", err)
+	}
+	mg := &v1alpha1.Script{
+		ObjectMeta: metav1.ObjectMeta{Name: "script", UID: "5f4a2c1e-0000-4000-8000-000000000001"},
+		Spec: v1alpha1.ScriptSpec{
+			ForProvider: v1alpha1.ScriptParameters{Lang: ptr.To("painless"), Source: ptr.To("return 1")},
+		},
+	}
+	mg.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+	meta.SetExternalName(mg, "script")
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&clusterv1beta1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: clusterv1beta1.ProviderConfigSpec{
+				Credentials: clusterv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceNone},
+				URL:         ptr.To("http://opensearch.test:9200"),
+			},
+		},
+		mg,
+	).WithStatusSubresource(mg).Build()
+
+	tfProvider := &tfschema.Provider{
+		Schema: map[string]*tfschema.Schema{url: {Type: tfschema.TypeString, Optional: true}},
+		ConfigureContextFunc: func(context.Context, *tfschema.ResourceData) (any, diag.Diagnostics) {
+			return "provider meta", nil
+		},
+	}
+	done := callbacks(make(chan error, 1))
+	c := tjcontroller.NewTerraformPluginSDKAsyncConnector(kube, tjcontroller.NewOperationStore(logging.NewNopLogger()),
+		TerraformSetupBuilder("", "", "", tfProvider, nil), r,
+		tjcontroller.WithTerraformPluginSDKAsyncCallbackProvider(done),
+		tjcontroller.WithTerraformPluginSDKAsyncLogger(logging.NewNopLogger()))
+
+	// connect observes the managed resource with a new external client, as
+	// every reconcile does.
+	connect := func(t *testing.T) managed.ExternalClient {
+		t.Helper()
+		ext, err := c.Connect(ctx, mg)
+		if err != nil {
+			t.Fatalf("Connect(...): %v", err)
+		}
+		if _, err := ext.Observe(ctx, mg); err != nil {
+			t.Fatalf("Observe(...): %v", err)
+		}
+		return ext
+	}
+	pcKey := ProviderConfigKey{GroupKind: clusterv1beta1.ProviderConfigGroupVersionKind.GroupKind(), Name: "default"}
+	drift := func() float64 {
+		return testutil.ToFloat64(metrics.DriftDetected.WithLabelValues(pcKey.String(), r.Kind))
+	}
+	// changes returns the changed attributes of a change log entry.
+	changes := func(e entry) []string {
+		return strings.Split(e.ad[changelogs.DetailChangedAttributes], ",")
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		if _, err := connect(t).Create(ctx, mg); err != nil {
+			t.Fatalf("Create(...): %v", err)
+		}
+		done.wait(t)
+		e, n := cl.last()
+		if n != 1 || e.op != changelogsv1alpha1.OperationType_OPERATION_TYPE_CREATE || e.err != nil || e.name != "script" {
+			t.Fatalf("last entry = %+v (%d entries), want a successful create of script", e, n)
+		}
+		if got := changes(e); !slices.Contains(got, "source") {
+			t.Errorf("changed attributes = %v, want source", got)
+		}
+		if got := e.ad[changelogs.DetailExternalName]; got != "script" {
+			t.Errorf("external name = %q, want script", got)
+		}
+		var state map[string]any
+		if err := json.Unmarshal([]byte(e.ad[changelogs.DetailStateAfter]), &state); err != nil || state["source"] != "return 1" {
+			t.Errorf("state after = %q, want source return 1", e.ad[changelogs.DetailStateAfter])
+		}
+	})
+
+	t.Run("FailedUpdate", func(t *testing.T) {
+		s.fail(true)
+		defer s.fail(false)
+		mg.Spec.ForProvider.Source = ptr.To("return 2")
+		if _, err := connect(t).Update(ctx, mg); err != nil {
+			t.Fatalf("Update(...): %v", err)
+		}
+		done.wait(t)
+		e, n := cl.last()
+		if n != 2 || e.op != changelogsv1alpha1.OperationType_OPERATION_TYPE_UPDATE || e.err == nil || e.err.Error() != "elastic: Error 409 (Conflict)" {
+			t.Fatalf("last entry = %+v (%d entries), want an update failed with the error of the operation", e, n)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		// The async update starts regardless of the error of the failed
+		// update it reports.
+		_, _ = connect(t).Update(ctx, mg)
+		done.wait(t)
+		e, n := cl.last()
+		if n != 3 || e.op != changelogsv1alpha1.OperationType_OPERATION_TYPE_UPDATE || e.err != nil {
+			t.Fatalf("last entry = %+v (%d entries), want a successful update", e, n)
+		}
+		if got := changes(e); len(got) != 1 || got[0] != "source" {
+			t.Errorf("changed attributes = %v, want [source]", got)
+		}
+		if got := drift(); got != 0 {
+			t.Errorf("drift = %v, want none for a changed desired state", got)
+		}
+		if e.ad[changelogs.DetailDrift] != "" {
+			t.Errorf("drift detail = %q, want none for a changed desired state", e.ad[changelogs.DetailDrift])
+		}
+	})
+
+	t.Run("Drift", func(t *testing.T) {
+		s.set("script", "tampered")
+		if _, err := connect(t).Update(ctx, mg); err != nil {
+			t.Fatalf("Update(...): %v", err)
+		}
+		done.wait(t)
+		e, n := cl.last()
+		if n != 4 || e.op != changelogsv1alpha1.OperationType_OPERATION_TYPE_UPDATE || e.err != nil {
+			t.Fatalf("last entry = %+v (%d entries), want a successful update", e, n)
+		}
+		if got := drift(); got != 1 {
+			t.Errorf("drift = %v, want 1", got)
+		}
+		if e.ad[changelogs.DetailDrift] != "true" {
+			t.Errorf("drift detail = %q, want true", e.ad[changelogs.DetailDrift])
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if _, err := connect(t).Delete(ctx, mg); err != nil {
+			t.Fatalf("Delete(...): %v", err)
+		}
+		done.wait(t)
+		e, n := cl.last()
+		if n != 5 || e.op != changelogsv1alpha1.OperationType_OPERATION_TYPE_DELETE || e.err != nil {
+			t.Fatalf("last entry = %+v (%d entries), want a successful delete", e, n)
+		}
+		if e.ad[changelogs.DetailStateAfter] != "{}" {
+			t.Errorf("state after = %q, want no attributes", e.ad[changelogs.DetailStateAfter])
+		}
+	})
+}
+
+func TestStatusCode(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want string
+	}{
+		"NoError": {},
+		"Elastic": {
+			err:  &elastic7.Error{Status: http.StatusNotFound},
+			want: "404",
+		},
+		"ElasticWrapped": {
+			err:  fmt.Errorf("error creating index mapping: %s", &elastic7.Error{Status: http.StatusBadRequest}),
+			want: "400",
+		},
+		"Diagnostics": {
+			err:  diagsError(diag.Errorf("error updating role: elastic: Error 409 (Conflict): version conflict [type=version_conflict_engine_exception]")),
+			want: "409",
+		},
+		"Status": {
+			err:  errors.Wrap(&StatusError{Method: http.MethodGet, Path: "/logs/_settings", StatusCode: http.StatusForbidden, Status: "403 Forbidden"}, "cannot get"),
+			want: "403",
+		},
+		"Unknown": {
+			err:  errors.New("connection refused"),
+			want: "error",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := statusCode(tc.err); got != tc.want {
+				t.Errorf("statusCode(%v) = %q, want %q", tc.err, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/pkg/errors"
+)
+
+const (
+	authInfoPath = "/_plugins/_security/authinfo"
+
+	errNewHTTPClient = "cannot create HTTP client"
+	errProbeRoot     = "cannot get cluster information"
+	errProbeAuthInfo = "cannot get authentication information"
+	errProbeEndpoint = "cannot probe endpoint %s"
+	errDecodeBody    = "cannot decode response body"
+)
+
+// ClusterInfo describes an OpenSearch cluster and the identity the provider
+// has in it, as observed by a probe.
+type ClusterInfo struct {
+	Distribution      string
+	Version           string
+	Name              string
+	AuthenticatedUser string
+	BackendRoles      []string
+
+	// Endpoint the cluster was observed through.
+	Endpoint string
+}
+
+type rootResponse struct {
+	ClusterName string `json:"cluster_name"`
+	Version     struct {
+		Distribution string `json:"distribution"`
+		Number       string `json:"number"`
+	} `json:"version"`
+}
+
+type authInfoResponse struct {
+	UserName     string   `json:"user_name"`
+	BackendRoles []string `json:"backend_roles"`
+}
+
+// A StatusError is returned by a probe if OpenSearch answers a request with
+// an unexpected HTTP status.
+type StatusError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Path, e.Status)
+}
+
+// Probe connects to the OpenSearch cluster of the supplied ProviderConfig and
+// configuration with `GET /` and asks the security plugin who the provider
+// is authenticated as. Clusters without the security plugin are reported
+// without an authenticated user. If the configuration configures several
+// endpoints, the health of each is checked and the cluster is reported as
+// observed through the first healthy one.
+func Probe(ctx context.Context, key ProviderConfigKey, cfg terraform.ProviderConfiguration) (*ClusterInfo, error) {
+	urls := endpointsOf(cfg)
+	if len(urls) == 0 {
+		return probeEndpoint(ctx, key, cfg)
+	}
+
+	var info *ClusterInfo
+	var firstErr error
+	for _, u := range urls {
+		i, err := probeEndpoint(ctx, key, withEndpoint(cfg, u))
+		endpoints.record(key, u, err)
+		switch {
+		case err != nil && firstErr == nil:
+			firstErr = errors.Wrapf(err, errProbeEndpoint, u)
+		case err == nil && info == nil:
+			info = i
+		}
+	}
+	if info == nil {
+		return nil, firstErr
+	}
+	return info, nil
+}
+
+// probeEndpoint probes the endpoint the supplied configuration uses.
+func probeEndpoint(ctx context.Context, key ProviderConfigKey, cfg terraform.ProviderConfiguration) (*ClusterInfo, error) {
+	conn, err := connections.get(key, cfg)
+	if err != nil {
+		return nil, err
+	}
+	hc, err := conn.httpClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	base := strings.TrimSuffix(stringSetting(cfg, url), "/")
+
+	root := &rootResponse{}
+	if err := getJSON(ctx, hc, base, "/", root); err != nil {
+		return nil, errors.Wrap(err, errProbeRoot)
+	}
+	info := &ClusterInfo{
+		Distribution: root.Version.Distribution,
+		Version:      root.Version.Number,
+		Name:         root.ClusterName,
+		Endpoint:     stringSetting(cfg, url),
+	}
+	if info.Distribution == "" {
+		// Elasticsearch and OpenSearch clusters in compatibility mode do
+		// not report a distribution.
+		info.Distribution = "elasticsearch"
+	}
+
+	ai := &authInfoResponse{}
+	err = getJSON(ctx, hc, base, authInfoPath, ai)
+	var se *StatusError
+	switch {
+	case errors.As(err, &se) && (se.StatusCode == http.StatusNotFound || se.StatusCode == http.StatusBadRequest):
+		return info, nil
+	case err != nil:
+		return nil, errors.Wrap(err, errProbeAuthInfo)
+	}
+	info.AuthenticatedUser = ai.UserName
+	info.BackendRoles = ai.BackendRoles
+	return info, nil
+}
+
+// getJSON decodes the JSON response to a GET request of the supplied path.
+func getJSON(ctx context.Context, hc *http.Client, base, path string, into any) error {
+	req, err := http.NewRequestWithContext(WithPathTemplate(ctx, path), http.MethodGet, base+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return &StatusError{Method: req.Method, Path: path, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(into), errDecodeBody)
+}
@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+const (
+	errRequestServiceAccountToken = "cannot request ServiceAccount token"
+)
+
+// A serviceAccountTokenSource requests tokens of a ServiceAccount with the
+// TokenRequest API.
+type serviceAccountTokenSource struct {
+	kube              client.Client
+	serviceAccount    namespacedv1beta1.ServiceAccountReference
+	audience          string
+	expirationSeconds *int64
+}
+
+func (s *serviceAccountTokenSource) Token() (*oauth2.Token, error) {
+	// The token source outlives the reconcile it was created in.
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRequestTimeout)
+	defer cancel()
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: s.serviceAccount.Namespace, Name: s.serviceAccount.Name}}
+	tr := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{s.audience},
+			ExpirationSeconds: s.expirationSeconds,
+		},
+	}
+	if err := s.kube.SubResource("token").Create(ctx, sa, tr); err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{
+		AccessToken: tr.Status.Token,
+		TokenType:   "Bearer",
+		Expiry:      tr.Status.ExpirationTimestamp.Time,
+	}, nil
+}
+
+// configureServiceAccountTokenAuth requests a token of a ServiceAccount and
+// configures it as bearer token.
+func configureServiceAccountTokenAuth(kube client.Client, a *namespacedv1beta1.ServiceAccountTokenAuth, cfg terraform.ProviderConfiguration) error {
+	src := &serviceAccountTokenSource{
+		kube:              kube,
+		serviceAccount:    a.ServiceAccountRef,
+		audience:          a.Audience,
+		expirationSeconds: a.ExpirationSeconds,
+	}
+	identity, err := digestOf(map[string]any{
+		"serviceAccount":    src.serviceAccount,
+		"audience":          src.audience,
+		"expirationSeconds": src.expirationSeconds,
+	})
+	if err != nil {
+		return err
+	}
+	t, err := tokenSources.get(identity, func() oauth2.TokenSource { return src }).Token()
+	if err != nil {
+		return errors.Wrap(err, errRequestServiceAccountToken)
+	}
+	cfg[token] = t.AccessToken
+	cfg[tokenName] = "Bearer"
+	cfg[tokenSourceSetting] = identity
+	return nil
+}
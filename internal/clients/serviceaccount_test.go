@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+func TestConfigureServiceAccountTokenAuth(t *testing.T) {
+	withTokenSources(t)
+	var requests []authenticationv1.TokenRequestSpec
+	kube := fake.NewClientBuilder().
+		WithObjects(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "opensearch"}}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, sub string, obj, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				if tr, ok := subResource.(*authenticationv1.TokenRequest); ok {
+					requests = append(requests, tr.Spec)
+				}
+				return c.SubResource(sub).Create(ctx, obj, subResource, opts...)
+			},
+		}).Build()
+	a := &namespacedv1beta1.ServiceAccountTokenAuth{
+		ServiceAccountRef: namespacedv1beta1.ServiceAccountReference{Namespace: "team-a", Name: "opensearch"},
+		Audience:          "opensearch",
+		ExpirationSeconds: ptr.To[int64](3600),
+	}
+
+	first, second := terraform.ProviderConfiguration{}, terraform.ProviderConfiguration{}
+	if err := configureServiceAccountTokenAuth(kube, a, first); err != nil {
+		t.Fatalf("configureServiceAccountTokenAuth(...): %v", err)
+	}
+	if err := configureServiceAccountTokenAuth(kube, a, second); err != nil {
+		t.Fatalf("configureServiceAccountTokenAuth(...): %v", err)
+	}
+	if first[token] != "fake-token" || first[tokenName] != "Bearer" {
+		t.Errorf("token: want Bearer fake-token, got %v %v", first[tokenName], first[token])
+	}
+	// The token does not expire soon, so that it is reused.
+	if len(requests) != 1 {
+		t.Fatalf("token requests: want 1, got %d", len(requests))
+	}
+	if got := requests[0]; len(got.Audiences) != 1 || got.Audiences[0] != "opensearch" || got.ExpirationSeconds == nil || *got.ExpirationSeconds != 3600 {
+		t.Errorf("token request: want audience opensearch and expiration 3600, got %+v", got)
+	}
+	if first[tokenSourceSetting] != second[tokenSourceSetting] {
+		t.Error("the token sources of the same ServiceAccount differ")
+	}
+
+	other := *a
+	other.Audience = "dashboards"
+	cfg := terraform.ProviderConfiguration{}
+	if err := configureServiceAccountTokenAuth(kube, &other, cfg); err != nil {
+		t.Fatalf("configureServiceAccountTokenAuth(...): %v", err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("token requests: want a second one for another audience, got %d", len(requests))
+	}
+	if cfg[tokenSourceSetting] == first[tokenSourceSetting] {
+		t.Error("the token sources of different audiences are the same")
+	}
+
+	missing := *a
+	missing.ServiceAccountRef.Name = "missing"
+	if err := configureServiceAccountTokenAuth(kube, &missing, terraform.ProviderConfiguration{}); err == nil {
+		t.Error("configureServiceAccountTokenAuth(...): want error for a missing ServiceAccount, got nil")
+	}
+}
@@ -0,0 +1,157 @@
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+const (
+	// tlsMaterialGracePeriod is how long superseded certificate files are
+	// kept after their last use, so that operations which were configured
+	// before a rotation can still finish.
+	tlsMaterialGracePeriod = 5 * time.Minute
+
+	pemBlockPrefix = "-----BEGIN "
+
+	errGetTLSMaterial   = "cannot get TLS material"
+	errWriteTLSMaterial = "cannot write TLS material"
+)
+
+// TLSMaterialRoot is the directory below which the certificate material of
+// each ProviderConfig is written.
+var TLSMaterialRoot = filepath.Join(os.TempDir(), "provider-opensearch", "tls")
+
+// tlsMaterialMu serializes writes, pruning and removal of TLS material.
+var tlsMaterialMu sync.Mutex
+
+// tlsSettings maps the Terraform provider settings that point to a PEM
+// file to the file names used for them.
+var tlsSettings = map[string]string{
+	cacertFile:     "ca",
+	clientCertPath: "cert",
+	clientKeyPath:  "key",
+}
+
+// configureTLS writes PEM content found in the credentials or referenced by
+// the TLS section of the ProviderConfig to the private directory of the
+// ProviderConfig and points the corresponding provider settings to it.
+func configureTLS(ctx context.Context, kube client.Client, key ProviderConfigKey, spec *namespacedv1beta1.ProviderConfigSpec, cfg map[string]any) error {
+	material := map[string][]byte{}
+	for setting := range tlsSettings {
+		if v, ok := cfg[setting].(string); ok && strings.Contains(v, pemBlockPrefix) {
+			material[setting] = []byte(v)
+		}
+	}
+	if spec.TLS != nil {
+		for setting, ref := range map[string]*xpv1.SecretKeySelector{
+			cacertFile:     spec.TLS.CACertSecretRef,
+			clientCertPath: spec.TLS.ClientCertSecretRef,
+			clientKeyPath:  spec.TLS.ClientKeySecretRef,
+		} {
+			if ref == nil {
+				continue
+			}
+			data, err := resource.ExtractSecret(ctx, kube, xpv1.CommonCredentialSelectors{SecretRef: ref})
+			if err != nil {
+				return errors.Wrap(err, errGetTLSMaterial)
+			}
+			material[setting] = data
+		}
+	}
+	if len(material) == 0 {
+		return nil
+	}
+
+	paths, err := writeTLSMaterial(key.dir(TLSMaterialRoot), material)
+	if err != nil {
+		return errors.Wrap(err, errWriteTLSMaterial)
+	}
+	for setting, path := range paths {
+		cfg[setting] = path
+	}
+	return nil
+}
+
+// writeTLSMaterial stores the supplied material in dir and returns the path
+// of each file. Files are named after a digest of their content, so a
+// rotated Secret results in new paths while unchanged material is only
+// written once. Files that were not used for longer than the grace period
+// are removed.
+func writeTLSMaterial(dir string, material map[string][]byte) (map[string]string, error) {
+	tlsMaterialMu.Lock()
+	defer tlsMaterialMu.Unlock()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	paths := make(map[string]string, len(material))
+	for setting, content := range material {
+		sum := sha256.Sum256(content)
+		path := filepath.Join(dir, tlsSettings[setting]+"-"+hex.EncodeToString(sum[:8])+".pem")
+		if err := writeFileOnce(path, content, now); err != nil {
+			return nil, err
+		}
+		paths[setting] = path
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || now.Sub(info.ModTime()) < tlsMaterialGracePeriod {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// writeFileOnce atomically writes content to path unless the file already
+// exists, in which case only its modification time is refreshed to mark
+// it as in use.
+func writeFileOnce(path string, content []byte, now time.Time) error {
+	if _, err := os.Stat(path); err == nil {
+		return os.Chtimes(path, now, now)
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	// The temporary file is gone after a successful rename.
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// RemoveTLSMaterial removes the certificate material written for the
+// supplied ProviderConfig.
+func RemoveTLSMaterial(key ProviderConfigKey) error {
+	tlsMaterialMu.Lock()
+	defer tlsMaterialMu.Unlock()
+	return os.RemoveAll(key.dir(TLSMaterialRoot))
+}
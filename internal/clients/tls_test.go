@@ -0,0 +1,116 @@
+package clients
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+)
+
+const (
+	testCA       = "-----BEGIN CERTIFICATE-----\nca\n-----END CERTIFICATE-----\n"
+	testRotated  = "-----BEGIN CERTIFICATE-----\nrotated\n-----END CERTIFICATE-----\n"
+	testSecretCA = "-----BEGIN CERTIFICATE-----\nsecret\n-----END CERTIFICATE-----\n"
+)
+
+func TestWriteTLSMaterial(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		t.Helper()
+		paths, err := writeTLSMaterial(dir, map[string][]byte{cacertFile: []byte(content)})
+		if err != nil {
+			t.Fatalf("writeTLSMaterial(...): %v", err)
+		}
+		return paths[cacertFile]
+	}
+	read := func(path string) string {
+		t.Helper()
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%s): %v", path, err)
+		}
+		return string(b)
+	}
+
+	first := write(testCA)
+	if got := read(first); got != testCA {
+		t.Errorf("content: want %q, got %q", testCA, got)
+	}
+	if again := write(testCA); again != first {
+		t.Errorf("paths of unchanged material: want %s, got %s", first, again)
+	}
+
+	// Superseded material is kept for the grace period.
+	rotated := write(testRotated)
+	if rotated == first {
+		t.Fatalf("paths of rotated material: want a new path, got %s", rotated)
+	}
+	if _, err := os.Stat(first); err != nil {
+		t.Errorf("superseded material was removed within the grace period: %v", err)
+	}
+
+	old := time.Now().Add(-tlsMaterialGracePeriod - time.Minute)
+	if err := os.Chtimes(first, old, old); err != nil {
+		t.Fatal(err)
+	}
+	write(testRotated)
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Errorf("superseded material was not removed after the grace period: %v", err)
+	}
+	if got := read(rotated); got != testRotated {
+		t.Errorf("content: want %q, got %q", testRotated, got)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(dir, ".tmp-*")); len(entries) != 0 {
+		t.Errorf("temporary files were left behind: %v", entries)
+	}
+}
+
+func TestConfigureTLS(t *testing.T) {
+	prev := TLSMaterialRoot
+	TLSMaterialRoot = t.TempDir()
+	t.Cleanup(func() { TLSMaterialRoot = prev })
+
+	key := ProviderConfigKey{GroupKind: schema.GroupKind{Group: "opensearch.upbound.io", Kind: "ProviderConfig"}, Name: "tls"}
+	kube := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "tls"},
+		Data:       map[string][]byte{"ca.crt": []byte(testSecretCA)},
+	}).Build()
+	spec := &namespacedv1beta1.ProviderConfigSpec{TLS: &namespacedv1beta1.TLSConfig{
+		CACertSecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "tls"}, Key: "ca.crt"},
+	}}
+	cfg := map[string]any{clientCertPath: testCA, clientKeyPath: "/etc/opensearch/client.key"}
+	if err := configureTLS(context.Background(), kube, key, spec, cfg); err != nil {
+		t.Fatalf("configureTLS(...): %v", err)
+	}
+
+	dir := key.dir(TLSMaterialRoot)
+	for setting, want := range map[string]string{cacertFile: testSecretCA, clientCertPath: testCA} {
+		path, _ := cfg[setting].(string)
+		if filepath.Dir(path) != dir {
+			t.Errorf("%s: want a file in %s, got %q", setting, dir, path)
+			continue
+		}
+		if b, err := os.ReadFile(path); err != nil || string(b) != want {
+			t.Errorf("%s: want %q, got %q (%v)", setting, want, b, err)
+		}
+	}
+	if got := cfg[clientKeyPath]; got != "/etc/opensearch/client.key" {
+		t.Errorf("%s: want the path unchanged, got %v", clientKeyPath, got)
+	}
+
+	if err := RemoveTLSMaterial(key); err != nil {
+		t.Fatalf("RemoveTLSMaterial(...): %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("TLS material was not removed: %v", err)
+	}
+}
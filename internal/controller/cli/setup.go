@@ -0,0 +1,118 @@
+// Package cli sets up controllers that reconcile managed resources with the
+// Terraform CLI instead of the in-process Terraform provider. The generated
+// controllers always reconcile in-process, so these controllers are built
+// from the resource configuration of the provider at runtime.
+package cli
+
+import (
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	xpfeature "github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/upjet/v2/pkg/config"
+	tjcontroller "github.com/crossplane/upjet/v2/pkg/controller"
+	"github.com/crossplane/upjet/v2/pkg/controller/handler"
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/tagesjump/provider-opensearch/internal/controller/selection"
+	"github.com/tagesjump/provider-opensearch/internal/features"
+	"github.com/tagesjump/provider-opensearch/internal/health"
+)
+
+const (
+	errNewManaged      = "cannot create managed resource of kind %s"
+	errRegisterWebhook = "cannot register webhook for the kind %s"
+)
+
+// SetupGated adds a controller that reconciles the managed resources of each
+// resource of the provider of the supplied options with the Terraform CLI,
+// once the CRD of the resource is available.
+func SetupGated(mgr ctrl.Manager, o tjcontroller.Options) error {
+	for _, r := range o.Provider.Resources {
+		o.Options.Gate.Register(func() {
+			if err := setup(mgr, o, r); err != nil {
+				mgr.GetLogger().Error(err, "unable to setup reconciler", "gvk", selection.GroupVersionKind(o.Provider, r).String())
+			}
+		}, selection.GroupVersionKind(o.Provider, r))
+	}
+	return nil
+}
+
+// setup adds a controller that reconciles the managed resources of the
+// supplied resource with the Terraform CLI. It mirrors the controllers
+// upjet generates for CLI-based resources.
+func setup(mgr ctrl.Manager, o tjcontroller.Options, r *config.Resource) error {
+	gvk := selection.GroupVersionKind(o.Provider, r)
+	obj, err := mgr.GetScheme().New(gvk)
+	if err != nil {
+		return errors.Wrapf(err, errNewManaged, gvk)
+	}
+	mg, ok := obj.(xpresource.Managed)
+	if !ok {
+		return errors.Errorf(errNewManaged, gvk)
+	}
+
+	name := managed.ControllerName(gvk.String())
+	var initializers managed.InitializerChain
+	for _, i := range r.InitializerFns {
+		initializers = append(initializers, i(mgr.GetClient()))
+	}
+	if !r.ExternalName.DisableNameInitializer {
+		initializers = append(initializers, managed.NewNameAsExternalName(mgr.GetClient()))
+	}
+	if r.UseAsync && o.Features.Enabled(xpfeature.EnableAlphaChangeLogs) {
+		// The managed reconciler records the change log entry of an
+		// operation once it returns, so that entries are only complete if
+		// operations run synchronously.
+		sync := *r
+		sync.UseAsync = false
+		r = &sync
+	}
+	eventHandler := handler.NewEventHandler(handler.WithLogger(o.Logger.WithValues("gvk", gvk)))
+	connectorOpts := []tjcontroller.Option{tjcontroller.WithLogger(o.Logger), tjcontroller.WithConnectorEventHandler(eventHandler)}
+	if r.UseAsync {
+		ac := tjcontroller.NewAPICallbacks(mgr, xpresource.ManagedKind(gvk), tjcontroller.WithEventHandler(eventHandler))
+		connectorOpts = append(connectorOpts, tjcontroller.WithCallbackProvider(ac))
+	}
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(tjcontroller.NewConnector(mgr.GetClient(), o.WorkspaceStore, o.SetupFn, r, connectorOpts...)),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithFinalizer(terraform.NewWorkspaceFinalizer(o.WorkspaceStore, xpresource.NewAPIFinalizer(mgr.GetClient(), managed.FinalizerName))),
+		managed.WithTimeout(3 * time.Minute),
+		managed.WithInitializers(initializers),
+		managed.WithPollInterval(o.PollInterval),
+	}
+	if o.PollJitter != 0 {
+		opts = append(opts, managed.WithPollJitterHook(o.PollJitter))
+	}
+	if o.Features.Enabled(features.EnableBetaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+	if o.MetricOptions != nil {
+		opts = append(opts, managed.WithMetricRecorder(o.MetricOptions.MRMetrics))
+	}
+	if o.Features.Enabled(xpfeature.EnableAlphaChangeLogs) {
+		opts = append(opts, managed.WithChangeLogger(o.ChangeLogOptions.ChangeLogger))
+	}
+
+	if o.StartWebhooks {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(mg).Complete(); err != nil {
+			return errors.Wrapf(err, errRegisterWebhook, gvk)
+		}
+	}
+
+	rec := managed.NewReconciler(mgr, xpresource.ManagedKind(gvk), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(xpresource.DesiredStateChanged()).
+		Watches(mg, eventHandler).
+		Complete(ratelimiter.NewReconciler(name, health.Track(health.InProgress, name, rec), o.GlobalRateLimiter))
+}
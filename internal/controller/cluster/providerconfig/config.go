@@ -1,6 +1,8 @@
 package providerconfig
 
 import (
+	"context"
+
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
@@ -9,6 +11,7 @@ import (
 	"github.com/crossplane/upjet/v2/pkg/controller"
 
 	"github.com/tagesjump/provider-opensearch/apis/cluster/v1beta1"
+	"github.com/tagesjump/provider-opensearch/internal/controller/connection"
 )
 
 // Setup adds a controller that reconciles ProviderConfigs by accounting for
@@ -22,14 +25,21 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		UsageList: v1beta1.ProviderConfigUsageListGroupVersionKind,
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.ProviderConfig{}).
-		Watches(&v1beta1.ProviderConfigUsage{}, &resource.EnqueueRequestForProviderConfig{}).
-		Complete(providerconfig.NewReconciler(mgr, of,
+		Watches(&v1beta1.ProviderConfigUsage{}, &resource.EnqueueRequestForProviderConfig{})
+	b, err := connection.WatchSecrets(context.TODO(), mgr, b, &v1beta1.ProviderConfig{}, &v1beta1.ProviderConfigList{})
+	if err != nil {
+		return err
+	}
+	return b.Complete(connection.NewReconciler(mgr, of,
+		providerconfig.NewReconciler(mgr, of,
 			providerconfig.WithLogger(o.Logger.WithValues("controller", name)),
-			providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+		connection.WithLogger(o.Logger.WithValues("controller", name)),
+		connection.WithPollInterval(o.PollInterval)))
 }
 
 // SetupGated adds a controller that reconciles ProviderConfigs by accounting for
@@ -0,0 +1,350 @@
+// Package connection contains the parts of the ProviderConfig controllers
+// that deal with the connection to OpenSearch and are shared by the
+// cluster-scoped and namespaced API groups.
+package connection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1beta1 "github.com/tagesjump/provider-opensearch/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/tagesjump/provider-opensearch/apis/namespaced/v1beta1"
+	"github.com/tagesjump/provider-opensearch/internal/clients"
+	"github.com/tagesjump/provider-opensearch/internal/metrics"
+	"github.com/tagesjump/provider-opensearch/internal/version"
+)
+
+const (
+	defaultPollInterval = 10 * time.Minute
+	probeTimeout        = 30 * time.Second
+
+	errResolveProviderConfig = "cannot resolve ProviderConfig"
+	errDigestConfiguration   = "cannot compute digest of provider configuration"
+	errUpdateStatus          = "cannot update ProviderConfig status"
+	errListUsages            = "cannot list ProviderConfigUsages"
+	errRequestReconcile      = "cannot request reconciliation of managed resource"
+)
+
+// AnnotationKeyProviderConfigRevision is set on managed resources to a
+// keyed digest of the connection settings of their ProviderConfig, which
+// reveals nothing about them, whenever these settings change, e.g. because
+// a referenced Secret was rotated. Setting it makes the managed resources
+// reconcile with the new settings right away.
+const AnnotationKeyProviderConfigRevision = "opensearch.upbound.io/provider-config-revision"
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithPollInterval specifies how often the Reconciler probes the cluster of
+// an unchanged ProviderConfig.
+func WithPollInterval(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pollInterval = d
+	}
+}
+
+// probeState records when a ProviderConfig was last probed, and with which
+// provider configuration.
+type probeState struct {
+	digest string
+	at     time.Time
+}
+
+// A Reconciler decorates the usage accounting reconciler of a ProviderConfig
+// kind. It periodically probes the OpenSearch cluster the ProviderConfig
+// connects to, reports the result in the status of the ProviderConfig, and
+// releases the connection state the provider keeps for a ProviderConfig
+// once it no longer exists.
+type Reconciler struct {
+	reconcile.Reconciler
+
+	client       client.Client
+	kind         schema.GroupKind
+	newConfig    func() resource.ProviderConfig
+	newUsageList func() resource.ProviderConfigUsageList
+	legacyPCU    bool
+	log          logging.Logger
+	pollInterval time.Duration
+
+	mu     sync.Mutex
+	probes map[clients.ProviderConfigKey]probeState
+}
+
+// NewReconciler returns a Reconciler for ProviderConfigs of the supplied
+// kinds that delegates usage accounting to the supplied reconciler.
+func NewReconciler(mgr ctrl.Manager, of resource.ProviderConfigKinds, r reconcile.Reconciler, o ...ReconcilerOption) *Reconciler {
+	_, legacyPCU := resource.MustCreateObject(of.Usage, mgr.GetScheme()).(resource.LegacyProviderConfigUsage)
+	rec := &Reconciler{
+		Reconciler: r,
+		client:     mgr.GetClient(),
+		kind:       of.Config.GroupKind(),
+		newConfig: func() resource.ProviderConfig {
+			return resource.MustCreateObject(of.Config, mgr.GetScheme()).(resource.ProviderConfig)
+		},
+		newUsageList: func() resource.ProviderConfigUsageList {
+			return resource.MustCreateObject(of.UsageList, mgr.GetScheme()).(resource.ProviderConfigUsageList)
+		},
+		legacyPCU:    legacyPCU,
+		log:          logging.NewNopLogger(),
+		pollInterval: defaultPollInterval,
+		probes:       map[clients.ProviderConfigKey]probeState{},
+	}
+	for _, ro := range o {
+		ro(rec)
+	}
+	return rec
+}
+
+// Reconcile a ProviderConfig.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.Reconciler.Reconcile(ctx, req)
+
+	key := clients.ProviderConfigKey{GroupKind: r.kind, Namespace: req.Namespace, Name: req.Name}
+	pc := r.newConfig()
+	if getErr := r.client.Get(ctx, req.NamespacedName, pc); getErr != nil {
+		if kerrors.IsNotFound(getErr) {
+			r.forget(key)
+			clients.EvictConnection(key)
+			clients.ForgetEndpoints(key)
+			clients.ForgetLimits(key)
+			metrics.ForgetProviderConfig(key.String())
+			if rmErr := clients.RemoveTLSMaterial(key); rmErr != nil {
+				r.log.Info("Cannot remove TLS material of deleted ProviderConfig", "request", req, "error", rmErr)
+			}
+		}
+		return result, err
+	}
+	if err != nil || meta.WasDeleted(pc) {
+		return result, err
+	}
+
+	after, err := r.probe(ctx, pc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if result.RequeueAfter == 0 || after < result.RequeueAfter {
+		result.RequeueAfter = after
+	}
+	return result, nil
+}
+
+// A probeResult is the outcome of a probe.
+type probeResult struct {
+	cluster   *clients.ClusterInfo
+	endpoints []clients.EndpointHealth
+	err       error
+}
+
+// probe the cluster of the supplied ProviderConfig unless it was probed
+// with the same configuration within the probe interval, and return when it
+// should be probed next. The managed resources using the ProviderConfig are
+// reconciled if its configuration changed since the last probe.
+func (r *Reconciler) probe(ctx context.Context, pc resource.ProviderConfig) (time.Duration, error) {
+	key, spec, err := clients.ResolveProviderConfig(pc)
+	if err != nil {
+		return 0, errors.Wrap(err, errResolveProviderConfig)
+	}
+	interval := r.probeInterval(spec)
+	cfg, err := clients.Configuration(ctx, r.client, key, spec)
+	if err != nil {
+		// Remember that the configuration changed, so that the managed
+		// resources are reconciled once it can be resolved again.
+		r.mu.Lock()
+		r.probes[key] = probeState{}
+		r.mu.Unlock()
+		return interval, r.updateStatus(ctx, pc, probeResult{err: err})
+	}
+	digest, err := clients.ConfigurationDigest(cfg)
+	if err != nil {
+		return 0, errors.Wrap(err, errDigestConfiguration)
+	}
+
+	r.mu.Lock()
+	last, ok := r.probes[key]
+	r.mu.Unlock()
+	if since := time.Since(last.at); ok && last.digest == digest && since < interval {
+		return interval - since, nil
+	}
+	if ok && last.digest != digest {
+		r.log.Debug("Connection settings changed, reconciling managed resources", "providerConfig", key)
+		if err := r.requestUsersReconcile(ctx, pc, digest); err != nil {
+			return 0, err
+		}
+	}
+
+	pctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	res := probeResult{}
+	res.cluster, res.err = clients.Probe(pctx, key, cfg)
+	res.endpoints = clients.Endpoints(key, cfg)
+	if res.err != nil {
+		r.log.Debug("Cannot connect to OpenSearch", "providerConfig", key, "error", res.err)
+	}
+
+	r.mu.Lock()
+	r.probes[key] = probeState{digest: digest, at: time.Now()}
+	r.mu.Unlock()
+	return interval, r.updateStatus(ctx, pc, res)
+}
+
+// probeInterval returns how often the cluster of a ProviderConfig with the
+// supplied spec is probed. ProviderConfigs with several endpoints are
+// probed as often as the health of their endpoints is checked.
+func (r *Reconciler) probeInterval(spec *namespacedv1beta1.ProviderConfigSpec) time.Duration {
+	if e := spec.Endpoints; e != nil && e.HealthCheckInterval != nil && e.HealthCheckInterval.Duration > 0 {
+		return min(r.pollInterval, e.HealthCheckInterval.Duration)
+	}
+	return r.pollInterval
+}
+
+// requestUsersReconcile requests the reconciliation of every managed
+// resource that uses the supplied ProviderConfig by annotating it with the
+// supplied digest of its connection settings.
+func (r *Reconciler) requestUsersReconcile(ctx context.Context, pc resource.ProviderConfig, digest string) error {
+	l := r.newUsageList()
+	opts := []client.ListOption{client.MatchingLabels{xpv1.LabelKeyProviderName: pc.GetName()}}
+	if !r.legacyPCU {
+		opts = append(opts, client.MatchingLabels{xpv1.LabelKeyProviderKind: r.kind.Kind})
+	}
+	if pc.GetNamespace() != "" {
+		opts = append(opts, client.InNamespace(pc.GetNamespace()))
+	}
+	if err := r.client.List(ctx, l, opts...); err != nil {
+		return errors.Wrap(err, errListUsages)
+	}
+
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AnnotationKeyProviderConfigRevision, digest[:16]))
+	for _, pcu := range l.GetItems() {
+		ref := pcu.GetResourceReference()
+		mg := &metav1.PartialObjectMetadata{}
+		mg.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+		mg.SetNamespace(pcu.GetNamespace())
+		mg.SetName(ref.Name)
+		if err := r.client.Patch(ctx, mg, client.RawPatch(types.MergePatchType, patch)); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errRequestReconcile)
+		}
+	}
+	return nil
+}
+
+// forget when the supplied ProviderConfig was last probed.
+func (r *Reconciler) forget(key clients.ProviderConfigKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.probes, key)
+}
+
+// updateStatus reports the outcome of a probe in the status of the supplied
+// ProviderConfig. The cluster status of the last successful probe is kept
+// if the probe failed.
+func (r *Reconciler) updateStatus(ctx context.Context, pc resource.ProviderConfig, res probeResult) error {
+	now := metav1.Now()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := r.newConfig()
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(pc), latest); err != nil {
+			return err
+		}
+		orig := latest.DeepCopyObject()
+		if res.err != nil {
+			latest.SetConditions(namespacedv1beta1.ConnectionFailed(res.err), xpv1.Unavailable().WithMessage(res.err.Error()))
+		} else {
+			latest.SetConditions(namespacedv1beta1.Connected(), xpv1.Available())
+			setClusterStatus(latest, res.cluster, now)
+		}
+		setEndpointStatus(latest, res.endpoints)
+		setVersionStatus(latest, version.Get())
+		if equality.Semantic.DeepEqual(orig, latest) {
+			return nil
+		}
+		return r.client.Status().Update(ctx, latest)
+	})
+	return errors.Wrap(err, errUpdateStatus)
+}
+
+// setClusterStatus sets the cluster status of the supplied ProviderConfig.
+func setClusterStatus(pc resource.ProviderConfig, info *clients.ClusterInfo, now metav1.Time) {
+	cs := &namespacedv1beta1.ClusterStatus{
+		Distribution:      info.Distribution,
+		Version:           info.Version,
+		Name:              info.Name,
+		AuthenticatedUser: info.AuthenticatedUser,
+		BackendRoles:      info.BackendRoles,
+		Endpoint:          info.Endpoint,
+		LastProbeTime:     &now,
+	}
+	switch pc := pc.(type) {
+	case *clusterv1beta1.ProviderConfig:
+		pc.Status.Cluster = (*clusterv1beta1.ClusterStatus)(cs)
+	case *namespacedv1beta1.ProviderConfig:
+		pc.Status.Cluster = cs
+	case *namespacedv1beta1.ClusterProviderConfig:
+		pc.Status.Cluster = cs
+	}
+}
+
+// setVersionStatus sets the version status of the supplied ProviderConfig.
+func setVersionStatus(pc resource.ProviderConfig, v version.Info) {
+	vs := &namespacedv1beta1.VersionStatus{
+		Provider:          v.Version,
+		Terraform:         v.TerraformVersion,
+		TerraformProvider: v.TerraformProviderVersion,
+	}
+	switch pc := pc.(type) {
+	case *clusterv1beta1.ProviderConfig:
+		pc.Status.Versions = (*clusterv1beta1.VersionStatus)(vs)
+	case *namespacedv1beta1.ProviderConfig:
+		pc.Status.Versions = vs
+	case *namespacedv1beta1.ClusterProviderConfig:
+		pc.Status.Versions = vs
+	}
+}
+
+// setEndpointStatus sets the endpoint status of the supplied ProviderConfig
+// to the health of its endpoints as of their last health check or request.
+func setEndpointStatus(pc resource.ProviderConfig, health []clients.EndpointHealth) {
+	var es []namespacedv1beta1.EndpointStatus
+	for _, h := range health {
+		e := namespacedv1beta1.EndpointStatus{URL: h.URL, Healthy: h.Healthy, Message: h.Message}
+		if !h.LastSuccess.IsZero() {
+			// The status is serialized with a precision of seconds.
+			t := metav1.NewTime(h.LastSuccess.Truncate(time.Second))
+			e.LastSuccessTime = &t
+		}
+		es = append(es, e)
+	}
+	switch pc := pc.(type) {
+	case *clusterv1beta1.ProviderConfig:
+		pc.Status.Endpoints = nil
+		for _, e := range es {
+			pc.Status.Endpoints = append(pc.Status.Endpoints, clusterv1beta1.EndpointStatus(e))
+		}
+	case *namespacedv1beta1.ProviderConfig:
+		pc.Status.Endpoints = es
+	case *namespacedv1beta1.ClusterProviderConfig:
+		pc.Status.Endpoints = es
+	}
+}
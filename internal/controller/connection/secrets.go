@@ -0,0 +1,77 @@
+package connection
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tagesjump/provider-opensearch/internal/clients"
+)
+
+const (
+	// secretRefsIndex indexes ProviderConfigs by the Secrets they reference.
+	secretRefsIndex = "spec.secretRefs"
+
+	errIndexSecretRefs = "cannot index ProviderConfigs by the Secrets they reference"
+)
+
+// WatchSecrets makes the controller of the supplied ProviderConfig kind
+// reconcile every ProviderConfig that references a Secret when that Secret
+// changes.
+func WatchSecrets(ctx context.Context, mgr ctrl.Manager, b *builder.Builder, pc client.Object, list client.ObjectList) (*builder.Builder, error) {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, pc, secretRefsIndex, indexSecretRefs); err != nil {
+		return nil, errors.Wrap(err, errIndexSecretRefs)
+	}
+	return b.Watches(&corev1.Secret{}, enqueueRequestsForSecret(mgr.GetClient(), list),
+		builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})), nil
+}
+
+// indexSecretRefs returns the namespaced names of the Secrets the supplied
+// ProviderConfig references.
+func indexSecretRefs(obj client.Object) []string {
+	_, spec, err := clients.ResolveProviderConfig(obj)
+	if err != nil {
+		return nil
+	}
+	refs := clients.SecretRefs(spec)
+	keys := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		keys = append(keys, ref.String())
+	}
+	return keys
+}
+
+// enqueueRequestsForSecret enqueues a request for each ProviderConfig of the
+// supplied list kind that references a Secret.
+func enqueueRequestsForSecret(kube client.Client, list client.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, s client.Object) []reconcile.Request {
+		l, ok := list.DeepCopyObject().(client.ObjectList)
+		if !ok {
+			return nil
+		}
+		key := types.NamespacedName{Namespace: s.GetNamespace(), Name: s.GetName()}
+		if err := kube.List(ctx, l, client.MatchingFields{secretRefsIndex: key.String()}); err != nil {
+			return nil
+		}
+		items, err := apimeta.ExtractList(l)
+		if err != nil {
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(items))
+		for _, o := range items {
+			if pc, ok := o.(client.Object); ok {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pc)})
+			}
+		}
+		return requests
+	})
+}
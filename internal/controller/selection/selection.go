@@ -0,0 +1,148 @@
+// Package selection selects the managed resource kinds whose controllers
+// run.
+package selection
+
+import (
+	"sort"
+	"strings"
+
+	xpcontroller "github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	errNoGroup = "no managed resource kind is in group %q"
+	errNoKind  = "no managed resource kind matches %q"
+)
+
+// GroupVersionKind returns the GroupVersionKind the supplied resource of the
+// supplied provider is generated as.
+func GroupVersionKind(p *config.Provider, r *config.Resource) schema.GroupVersionKind {
+	group := p.RootGroup
+	if r.ShortGroup != "" {
+		group = r.ShortGroup + "." + p.RootGroup
+	}
+	return schema.GroupVersionKind{Group: group, Version: r.Version, Kind: r.Kind}
+}
+
+// A Selector selects the managed resource kinds whose controllers run.
+type Selector struct {
+	selected map[schema.GroupVersionKind]bool
+}
+
+// New returns a Selector that selects the managed resource kinds of the
+// supplied providers that are in one of the supplied groups, or in any
+// group if none are supplied, unless they are one of the supplied disabled
+// kinds. Groups match by their short or full name, e.g. index or
+// index.opensearch.upbound.io. Kinds match by their name, optionally
+// qualified with the short or full name of their group, e.g. Policy.ism.
+// Both match case-insensitively. It returns an error if a group or kind
+// matches no managed resource kind.
+func New(groups, disabledKinds []string, providers ...*config.Provider) (*Selector, error) {
+	s := &Selector{selected: map[schema.GroupVersionKind]bool{}}
+	matchedGroups := map[string]bool{}
+	matchedKinds := map[string]bool{}
+	for _, p := range providers {
+		for _, r := range p.Resources {
+			gvk := GroupVersionKind(p, r)
+			g := matchAny(groups, []string{r.ShortGroup, gvk.Group})
+			k := matchAny(disabledKinds, kindNames(gvk.Kind, r.ShortGroup, gvk.Group))
+			matchedGroups[g] = true
+			matchedKinds[k] = true
+			s.selected[gvk] = (len(groups) == 0 || g != "") && k == ""
+		}
+	}
+	for _, g := range groups {
+		if !matchedGroups[g] {
+			return nil, errors.Errorf(errNoGroup, g)
+		}
+	}
+	for _, k := range disabledKinds {
+		if !matchedKinds[k] {
+			return nil, errors.Errorf(errNoKind, k)
+		}
+	}
+	return s, nil
+}
+
+// kindNames returns the names a kind matches by.
+func kindNames(kind, shortGroup, fullGroup string) []string {
+	return []string{kind, kind + "." + shortGroup, kind + "." + fullGroup}
+}
+
+// matchAny returns the first of the supplied patterns that case-insensitively
+// equals one of the supplied names, or an empty string.
+func matchAny(patterns, names []string) string {
+	for _, p := range patterns {
+		for _, n := range names {
+			if n != "" && strings.EqualFold(p, n) {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// Selected returns true if the controller of the supplied kind should run.
+// Kinds that are not managed resource kinds, e.g. ProviderConfigs, are
+// always selected.
+func (s *Selector) Selected(gvk schema.GroupVersionKind) bool {
+	selected, ok := s.selected[gvk]
+	return !ok || selected
+}
+
+// Kinds returns the selected managed resource kinds, qualified with their
+// group.
+func (s *Selector) Kinds() []string {
+	kinds := make([]string, 0, len(s.selected))
+	for gvk, selected := range s.selected {
+		if selected {
+			kinds = append(kinds, gvk.GroupKind().String())
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Gate returns a gate that registers the callbacks of the supplied gate
+// only if all of their kinds are selected. The gated setup functions of
+// the controllers register their callbacks with it, so that only the
+// controllers of the selected kinds are set up.
+func (s *Selector) Gate(g xpcontroller.Gate) xpcontroller.Gate {
+	return &gate{gate: g, selector: s}
+}
+
+type gate struct {
+	gate     xpcontroller.Gate
+	selector *Selector
+}
+
+func (g *gate) Register(callback func(), gvks ...schema.GroupVersionKind) {
+	for _, gvk := range gvks {
+		if !g.selector.Selected(gvk) {
+			return
+		}
+	}
+	g.gate.Register(callback, gvks...)
+}
+
+func (g *gate) Set(gvk schema.GroupVersionKind, ready bool) bool {
+	return g.gate.Set(gvk, ready)
+}
+
+// An ImmediateGate runs callbacks as soon as they are registered. It lets
+// the gated setup functions of the controllers be used without waiting for
+// their CRDs.
+type ImmediateGate struct{}
+
+// Register runs the supplied callback.
+func (ImmediateGate) Register(callback func(), _ ...schema.GroupVersionKind) {
+	callback()
+}
+
+// Set does nothing.
+func (ImmediateGate) Set(schema.GroupVersionKind, bool) bool {
+	return false
+}
@@ -0,0 +1,126 @@
+package selection
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testProvider() *config.Provider {
+	return &config.Provider{
+		RootGroup: "opensearch.upbound.io",
+		Resources: map[string]*config.Resource{
+			"opensearch_index":      {Name: "opensearch_index", ShortGroup: "index", Version: "v1alpha1", Kind: "Index"},
+			"opensearch_index_tpl":  {Name: "opensearch_index_tpl", ShortGroup: "index", Version: "v1alpha1", Kind: "Template"},
+			"opensearch_ism_policy": {Name: "opensearch_ism_policy", ShortGroup: "ism", Version: "v1alpha1", Kind: "Policy"},
+			"opensearch_sm_policy":  {Name: "opensearch_sm_policy", ShortGroup: "sm", Version: "v1alpha1", Kind: "Policy"},
+			"opensearch_script":     {Name: "opensearch_script", Version: "v1alpha1", Kind: "Script"},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	cases := map[string]struct {
+		groups   []string
+		disabled []string
+		want     []string
+		wantErr  bool
+	}{
+		"All": {
+			want: []string{"Index.index.opensearch.upbound.io", "Policy.ism.opensearch.upbound.io", "Policy.sm.opensearch.upbound.io", "Script.opensearch.upbound.io", "Template.index.opensearch.upbound.io"},
+		},
+		"ShortGroup": {
+			groups: []string{"index"},
+			want:   []string{"Index.index.opensearch.upbound.io", "Template.index.opensearch.upbound.io"},
+		},
+		"FullGroupCaseInsensitive": {
+			groups: []string{"ISM.opensearch.upbound.io"},
+			want:   []string{"Policy.ism.opensearch.upbound.io"},
+		},
+		"RootGroup": {
+			groups: []string{"opensearch.upbound.io"},
+			want:   []string{"Script.opensearch.upbound.io"},
+		},
+		"DisabledKind": {
+			groups:   []string{"index"},
+			disabled: []string{"template"},
+			want:     []string{"Index.index.opensearch.upbound.io"},
+		},
+		"DisabledQualifiedKind": {
+			disabled: []string{"Policy.ism"},
+			want:     []string{"Index.index.opensearch.upbound.io", "Policy.sm.opensearch.upbound.io", "Script.opensearch.upbound.io", "Template.index.opensearch.upbound.io"},
+		},
+		"DisabledUnqualifiedKindInSeveralGroups": {
+			disabled: []string{"Policy"},
+			want:     []string{"Index.index.opensearch.upbound.io", "Script.opensearch.upbound.io", "Template.index.opensearch.upbound.io"},
+		},
+		"UnknownGroup": {
+			groups:  []string{"indices"},
+			wantErr: true,
+		},
+		"UnknownKind": {
+			disabled: []string{"Policy.index"},
+			wantErr:  true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := New(tc.groups, tc.disabled, testProvider())
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("New(...): want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New(...): %v", err)
+			}
+			if got := s.Kinds(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Kinds(): want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+type recordingGate struct {
+	registered [][]schema.GroupVersionKind
+}
+
+func (g *recordingGate) Register(_ func(), gvks ...schema.GroupVersionKind) {
+	g.registered = append(g.registered, gvks)
+}
+
+func (g *recordingGate) Set(schema.GroupVersionKind, bool) bool {
+	return false
+}
+
+func TestGate(t *testing.T) {
+	s, err := New([]string{"index"}, nil, testProvider())
+	if err != nil {
+		t.Fatalf("New(...): %v", err)
+	}
+	index := schema.GroupVersionKind{Group: "index.opensearch.upbound.io", Version: "v1alpha1", Kind: "Index"}
+	script := schema.GroupVersionKind{Group: "opensearch.upbound.io", Version: "v1alpha1", Kind: "Script"}
+	pc := schema.GroupVersionKind{Group: "opensearch.upbound.io", Version: "v1beta1", Kind: "ProviderConfig"}
+
+	rg := &recordingGate{}
+	g := s.Gate(rg)
+	g.Register(func() {}, index)
+	g.Register(func() {}, script)
+	g.Register(func() {}, index, script)
+	g.Register(func() {}, pc)
+	want := [][]schema.GroupVersionKind{{index}, {pc}}
+	if !reflect.DeepEqual(rg.registered, want) {
+		t.Errorf("registered: want %v, got %v", want, rg.registered)
+	}
+}
+
+func TestImmediateGate(t *testing.T) {
+	ran := false
+	ImmediateGate{}.Register(func() { ran = true })
+	if !ran {
+		t.Error("Register(...): callback did not run")
+	}
+}
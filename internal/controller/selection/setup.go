@@ -0,0 +1,128 @@
+package selection
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// A SetupManager is a manager whose logger records the errors the gated
+// setup functions of controllers log until Err is called. The callbacks
+// they register with a gate cannot return the errors of setting up a
+// controller.
+type SetupManager struct {
+	ctrl.Manager
+
+	sink *errorSink
+}
+
+// NewSetupManager returns a SetupManager that wraps the supplied manager.
+// Errors are logged with the supplied logger until Err is called,
+// everything else with the logger of the manager.
+func NewSetupManager(mgr ctrl.Manager, errLog logr.Logger) *SetupManager {
+	return &SetupManager{Manager: mgr, sink: &errorSink{next: mgr.GetLogger(), errLog: errLog, errs: &errorList{}}}
+}
+
+// GetLogger returns the logger of the manager, which records errors until
+// Err is called. Afterwards, it returns the logger of the wrapped manager.
+func (m *SetupManager) GetLogger() logr.Logger {
+	if m.sink.errs.isStopped() {
+		return m.Manager.GetLogger()
+	}
+	return logr.New(m.sink)
+}
+
+// Err stops recording errors and returns the errors logged so far, or nil
+// if none were. Controllers that were set up while errors were recorded
+// keep a logger that logs like the logger of the wrapped manager from then
+// on.
+func (m *SetupManager) Err() error {
+	return m.sink.errs.stop()
+}
+
+type errorList struct {
+	mu      sync.Mutex
+	errs    []error
+	stopped bool
+}
+
+// add the supplied error, and return false if errors are no longer
+// recorded.
+func (l *errorList) add(err error) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopped {
+		return false
+	}
+	l.errs = append(l.errs, err)
+	return true
+}
+
+func (l *errorList) stop() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = true
+	return kerrors.NewAggregate(l.errs)
+}
+
+func (l *errorList) isStopped() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stopped
+}
+
+// An errorSink records errors and logs them with errLog. It logs
+// everything else, and errors once recording stopped, with next.
+type errorSink struct {
+	next   logr.Logger
+	errLog logr.Logger
+	errs   *errorList
+}
+
+func (s *errorSink) Init(logr.RuntimeInfo) {}
+
+func (s *errorSink) Enabled(level int) bool {
+	return s.next.V(level).Enabled()
+}
+
+func (s *errorSink) Info(level int, msg string, kv ...any) {
+	s.next.V(level).Info(msg, kv...)
+}
+
+func (s *errorSink) Error(err error, msg string, kv ...any) {
+	pairs := make([]string, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", kv[i], kv[i+1]))
+	}
+	what := msg
+	if len(pairs) > 0 {
+		what += " (" + strings.Join(pairs, ", ") + ")"
+	}
+	if !s.errs.add(errors.Wrap(err, what)) {
+		s.next.Error(err, msg, kv...)
+		return
+	}
+	s.errLog.Error(err, msg, kv...)
+}
+
+// WithValues returns a sink with the supplied values. Once recording
+// stopped, it returns the sink of the next logger, so that the loggers of
+// controllers derived from it per reconcile no longer go through this one.
+func (s *errorSink) WithValues(kv ...any) logr.LogSink {
+	if s.errs.isStopped() {
+		return s.next.WithValues(kv...).GetSink()
+	}
+	return &errorSink{next: s.next.WithValues(kv...), errLog: s.errLog.WithValues(kv...), errs: s.errs}
+}
+
+func (s *errorSink) WithName(name string) logr.LogSink {
+	if s.errs.isStopped() {
+		return s.next.WithName(name).GetSink()
+	}
+	return &errorSink{next: s.next.WithName(name), errLog: s.errLog.WithName(name), errs: s.errs}
+}
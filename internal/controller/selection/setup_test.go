@@ -0,0 +1,62 @@
+package selection
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+type manager struct {
+	ctrl.Manager
+
+	log logr.Logger
+}
+
+func (m manager) GetLogger() logr.Logger {
+	return m.log
+}
+
+func TestSetupManager(t *testing.T) {
+	var logged, mgrLogged []string
+	errLog := funcr.New(func(_, args string) { logged = append(logged, args) }, funcr.Options{})
+	mgrLog := funcr.New(func(_, args string) { mgrLogged = append(mgrLogged, args) }, funcr.Options{})
+	m := NewSetupManager(manager{log: mgrLog}, errLog)
+
+	// Controllers keep the logger they were set up with.
+	var controllerLog logr.Logger
+	ImmediateGate{}.Register(func() {
+		controllerLog = m.GetLogger().WithName("index")
+		m.GetLogger().Info("set up reconciler")
+	})
+	ImmediateGate{}.Register(func() {
+		m.GetLogger().Error(errors.New("boom"), "unable to setup reconciler", "gvk", "Index")
+	})
+	want := "unable to setup reconciler (gvk=Index): boom"
+	if err := m.Err(); err == nil || err.Error() != want {
+		t.Errorf("Err() = %v, want %s", err, want)
+	}
+	if len(logged) != 1 {
+		t.Errorf("logged %v, want the error", logged)
+	}
+
+	// Errors are no longer recorded once setup returned.
+	mgrLogged = nil
+	controllerLog.Error(errors.New("conflict"), "Reconciler error")
+	controllerLog.WithValues("request", "logs").Error(errors.New("conflict"), "Reconciler error")
+	m.GetLogger().Error(errors.New("boom"), "unable to setup reconciler", "gvk", "Script")
+	if err := m.Err(); err == nil || err.Error() != want {
+		t.Errorf("Err() = %v, want %s", err, want)
+	}
+	if len(logged) != 1 {
+		t.Errorf("logged %v, want only the error of the setup", logged)
+	}
+	if len(mgrLogged) != 3 {
+		t.Errorf("manager logged %v, want the errors after the setup", mgrLogged)
+	}
+	if _, ok := controllerLog.WithValues("request", "logs").GetSink().(*errorSink); ok {
+		t.Error("loggers derived once setup returned still record errors")
+	}
+}
@@ -7,6 +7,10 @@ const (
 	// EnableAlphaExternalSecretStores enables alpha support for
 	// External Secret Stores. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/390ddd/design/design-doc-external-secret-stores.md
+	//
+	// Deprecated: External Secret Stores were removed in Crossplane v2 and
+	// crossplane-runtime v2 no longer provides the StoreConfig API or the
+	// connection publishers backing it. The flag is never enabled.
 	EnableAlphaExternalSecretStores feature.Flag = "EnableAlphaExternalSecretStores"
 
 	// EnableBetaManagementPolicies enables beta support for
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"net/http"
+	"sync"
+
+	xpcontroller "github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	errNoCRD    = "no CustomResourceDefinition is established yet"
+	errNotSetUp = "controllers of %d established kinds are not set up yet"
+)
+
+// A Gate is a controller gate that knows whether the controllers of all
+// established CustomResourceDefinitions are set up.
+type Gate struct {
+	gate xpcontroller.Gate
+
+	mu          sync.Mutex
+	established map[schema.GroupVersionKind]bool
+	pending     map[*registration]bool
+}
+
+type registration struct {
+	gvks []schema.GroupVersionKind
+}
+
+// NewGate returns a Gate that wraps the supplied gate.
+func NewGate(g xpcontroller.Gate) *Gate {
+	return &Gate{
+		gate:        g,
+		established: map[schema.GroupVersionKind]bool{},
+		pending:     map[*registration]bool{},
+	}
+}
+
+// Register registers the supplied callback with the wrapped gate.
+func (g *Gate) Register(callback func(), gvks ...schema.GroupVersionKind) {
+	r := &registration{gvks: gvks}
+	g.mu.Lock()
+	g.pending[r] = true
+	g.mu.Unlock()
+	g.gate.Register(func() {
+		callback()
+		g.mu.Lock()
+		delete(g.pending, r)
+		g.mu.Unlock()
+	}, gvks...)
+}
+
+// Set sets the supplied condition of the wrapped gate.
+func (g *Gate) Set(gvk schema.GroupVersionKind, ready bool) bool {
+	g.mu.Lock()
+	g.established[gvk] = ready
+	g.mu.Unlock()
+	return g.gate.Set(gvk, ready)
+}
+
+// Check fails until a CustomResourceDefinition is established and the
+// controllers of all established CustomResourceDefinitions are set up.
+func (g *Gate) Check(_ *http.Request) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.established) == 0 {
+		return errors.New(errNoCRD)
+	}
+	n := 0
+	for r := range g.pending {
+		if g.allEstablished(r.gvks) {
+			n++
+		}
+	}
+	if n > 0 {
+		return errors.Errorf(errNotSetUp, n)
+	}
+	return nil
+}
+
+func (g *Gate) allEstablished(gvks []schema.GroupVersionKind) bool {
+	for _, gvk := range gvks {
+		if !g.established[gvk] {
+			return false
+		}
+	}
+	return true
+}
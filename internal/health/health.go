@@ -0,0 +1,117 @@
+// Package health contains the liveness and readiness checks of the
+// provider.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	cacheSyncTimeout = time.Second
+
+	errCacheNotSynced = "cache is not synced"
+	errNotElected     = "not elected as leader"
+	errStalled        = "%s has not completed for %s"
+)
+
+// InProgress tracks the Terraform operations and reconciles that are in
+// progress.
+var InProgress = &Tracker{started: map[uint64]started{}}
+
+type started struct {
+	what string
+	at   time.Time
+}
+
+// A Tracker tracks work that is in progress.
+type Tracker struct {
+	mu      sync.Mutex
+	next    uint64
+	started map[uint64]started
+}
+
+// Start records that the supplied work started, and returns a function that
+// must be called once it completed.
+func (t *Tracker) Start(what string) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	id := t.next
+	t.next++
+	t.started[id] = started{what: what, at: time.Now()}
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.started, id)
+	}
+}
+
+// oldest returns the work that has been in progress the longest.
+func (t *Tracker) oldest() (started, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var o started
+	found := false
+	for _, s := range t.started {
+		if !found || s.at.Before(o.at) {
+			o, found = s, true
+		}
+	}
+	return o, found
+}
+
+// StallChecker returns a check that fails if any work has been in progress
+// for longer than the supplied window, e.g. because a Terraform operation
+// hangs. Having no work in progress is not a stall.
+func (t *Tracker) StallChecker(window time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		if o, ok := t.oldest(); ok {
+			if d := time.Since(o.at); d > window {
+				return errors.Errorf(errStalled, o.what, d.Round(time.Second))
+			}
+		}
+		return nil
+	}
+}
+
+// Track returns a reconciler that records the reconciles of the supplied
+// reconciler of the supplied controller in the supplied tracker.
+func Track(t *Tracker, controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		defer t.Start("reconcile of " + req.String() + " by " + controller)()
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// CacheSyncChecker returns a check that fails until the supplied cache is
+// synced.
+func CacheSyncChecker(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New(errCacheNotSynced)
+		}
+		return nil
+	}
+}
+
+// LeaderChecker returns a check that fails until the supplied channel is
+// closed, i.e. until the controller manager is elected as leader.
+func LeaderChecker(elected <-chan struct{}) healthz.Checker {
+	return func(_ *http.Request) error {
+		select {
+		case <-elected:
+			return nil
+		default:
+			return errors.New(errNotElected)
+		}
+	}
+}
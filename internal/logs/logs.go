@@ -0,0 +1,64 @@
+// Package logs configures the logging of the provider. It writes structured
+// logs whose reconcile log lines carry the same keys no matter whether they
+// were written by crossplane-runtime, upjet or the provider.
+package logs
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// Log formats.
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Log levels.
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelError = "error"
+)
+
+const (
+	errUnknownLevel = "unknown log level %q"
+)
+
+// New returns a logger that writes logs of at least the supplied level in
+// the supplied format.
+func New(level, format string) (logr.Logger, error) {
+	var lvl zapcore.Level
+	switch level {
+	case LevelDebug:
+		lvl = zapcore.DebugLevel
+	case LevelInfo:
+		lvl = zapcore.InfoLevel
+	case LevelError:
+		lvl = zapcore.ErrorLevel
+	default:
+		return logr.Logger{}, errors.Errorf(errUnknownLevel, level)
+	}
+	opts := []zap.Opts{zap.Level(lvl), zap.StacktraceLevel(zapcore.PanicLevel)}
+	if format == FormatConsole {
+		opts = append(opts, zap.ConsoleEncoder(encoderConfig))
+	} else {
+		opts = append(opts, zap.JSONEncoder(encoderConfig))
+	}
+	return zap.New(opts...), nil
+}
+
+// encoderConfig sets the names and encodings of the fields every log line
+// has.
+func encoderConfig(ec *zapcore.EncoderConfig) {
+	ec.TimeKey = "ts"
+	ec.LevelKey = "level"
+	ec.NameKey = "logger"
+	ec.CallerKey = "caller"
+	ec.MessageKey = "msg"
+	ec.StacktraceKey = "stacktrace"
+	ec.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	ec.EncodeDuration = zapcore.StringDurationEncoder
+}
@@ -0,0 +1,199 @@
+package logs
+
+import (
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/lru"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Keys of reconcile log lines.
+const (
+	KeyGVK            = "gvk"
+	KeyName           = "name"
+	KeyNamespace      = "namespace"
+	KeyProviderConfig = "providerConfig"
+	KeyExternalName   = "externalName"
+	KeyOperationID    = "operationID"
+)
+
+// Keys crossplane-runtime and upjet log with.
+const (
+	keyController   = "controller"
+	keyRequest      = "request"
+	keyUID          = "uid"
+	keyExternalName = "external-name"
+)
+
+// maxResources is the number of managed resources whose ProviderConfig is
+// remembered.
+const maxResources = 10000
+
+var (
+	kindsMu sync.RWMutex
+	kinds   = map[string]string{}
+
+	providerConfigs = lru.New(maxResources)
+)
+
+// RegisterKind registers the supplied managed resource kind, so that the
+// log lines of its controller carry it.
+func RegisterKind(gvk schema.GroupVersionKind) {
+	kindsMu.Lock()
+	defer kindsMu.Unlock()
+	kinds[managed.ControllerName(gvk.String())] = gvk.String()
+}
+
+// SetProviderConfig records the ProviderConfig the managed resource with the
+// supplied UID uses, so that its log lines carry it.
+func SetProviderConfig(uid types.UID, pc string) {
+	providerConfigs.Add(uid, pc)
+}
+
+// WithReconcileKeys returns a logger that writes the log lines of the
+// supplied logger with the keys of this package: the managed resource kind
+// instead of the name of its controller, the name and namespace instead of
+// the reconcile request, an operation ID per reconcile, the external name
+// and the ProviderConfig of the reconciled managed resource. A key that is
+// logged more than once is only written once, with its latest value.
+func WithReconcileKeys(l logr.Logger) logr.Logger {
+	ls := l.GetSink()
+	if cd, ok := ls.(logr.CallDepthLogSink); ok {
+		// The sink adds a frame between the logger and the wrapped sink.
+		ls = cd.WithCallDepth(1)
+	}
+	return logr.New(&sink{sink: ls})
+}
+
+type sink struct {
+	sink   logr.LogSink
+	values []any
+}
+
+// Init does nothing. The wrapped sink is initialized already.
+func (s *sink) Init(logr.RuntimeInfo) {}
+
+func (s *sink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+func (s *sink) Info(level int, msg string, kv ...any) {
+	s.sink.Info(level, msg, s.keyValues(kv)...)
+}
+
+func (s *sink) Error(err error, msg string, kv ...any) {
+	s.sink.Error(err, msg, s.keyValues(kv)...)
+}
+
+func (s *sink) WithValues(kv ...any) logr.LogSink {
+	return &sink{sink: s.sink, values: merge(s.values, normalize(kv))}
+}
+
+func (s *sink) WithName(name string) logr.LogSink {
+	return &sink{sink: s.sink.WithName(name), values: s.values}
+}
+
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	cd, ok := s.sink.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	return &sink{sink: cd.WithCallDepth(depth), values: s.values}
+}
+
+// keyValues returns the values of the sink merged with the supplied ones,
+// and the ProviderConfig of the managed resource they are about, if known.
+func (s *sink) keyValues(kv []any) []any {
+	all := merge(s.values, normalize(kv))
+	if uid, ok := value(all, keyUID); ok {
+		if pc, ok := providerConfigs.Get(toUID(uid)); ok {
+			all = merge(all, []any{KeyProviderConfig, pc})
+		}
+	}
+	return all
+}
+
+// normalize returns the supplied key value pairs with the keys of
+// crossplane-runtime translated to the keys of this package.
+func normalize(kv []any) []any {
+	out := make([]any, 0, len(kv))
+	for i := 0; i+1 < len(kv); i += 2 {
+		k, v := kv[i], kv[i+1]
+		switch k {
+		case keyController:
+			out = append(out, k, v)
+			if name, ok := v.(string); ok {
+				if gvk, ok := kind(name); ok {
+					out = append(out, KeyGVK, gvk)
+				}
+			}
+		case keyRequest:
+			req, ok := v.(reconcile.Request)
+			if !ok {
+				out = append(out, k, v)
+				continue
+			}
+			if req.Namespace != "" {
+				out = append(out, KeyNamespace, req.Namespace)
+			}
+			out = append(out, KeyName, req.Name, KeyOperationID, uuid.NewString())
+		case keyExternalName:
+			out = append(out, KeyExternalName, v)
+		default:
+			out = append(out, k, v)
+		}
+	}
+	return out
+}
+
+func kind(controller string) (string, bool) {
+	kindsMu.RLock()
+	defer kindsMu.RUnlock()
+	gvk, ok := kinds[controller]
+	return gvk, ok
+}
+
+// merge returns the supplied key value pairs appended to the supplied
+// values. Values of keys that exist already replace the existing values.
+func merge(values, kv []any) []any {
+	out := make([]any, len(values), len(values)+len(kv))
+	copy(out, values)
+	for i := 0; i+1 < len(kv); i += 2 {
+		replaced := false
+		for j := 0; j+1 < len(out); j += 2 {
+			if out[j] == kv[i] {
+				out[j+1], replaced = kv[i+1], true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, kv[i], kv[i+1])
+		}
+	}
+	return out
+}
+
+// value returns the value of the supplied key.
+func value(kv []any, key string) (any, bool) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i] == key {
+			return kv[i+1], true
+		}
+	}
+	return nil, false
+}
+
+func toUID(v any) types.UID {
+	switch uid := v.(type) {
+	case types.UID:
+		return uid
+	case string:
+		return types.UID(uid)
+	}
+	return ""
+}
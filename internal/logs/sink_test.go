@@ -0,0 +1,102 @@
+package logs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// recordingSink records the key value pairs of the log lines written to it.
+type recordingSink struct {
+	lines *[][]any
+	kv    []any
+}
+
+func (s *recordingSink) Init(logr.RuntimeInfo) {}
+func (s *recordingSink) Enabled(int) bool      { return true }
+
+func (s *recordingSink) Info(_ int, _ string, kv ...any) {
+	*s.lines = append(*s.lines, append(append([]any{}, s.kv...), kv...))
+}
+
+func (s *recordingSink) Error(err error, msg string, kv ...any) {
+	s.Info(0, msg, append(kv, "error", err)...)
+}
+
+func (s *recordingSink) WithValues(kv ...any) logr.LogSink {
+	return &recordingSink{lines: s.lines, kv: append(append([]any{}, s.kv...), kv...)}
+}
+
+func (s *recordingSink) WithName(string) logr.LogSink { return s }
+
+func TestWithReconcileKeys(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "index.opensearch.upbound.io", Version: "v1alpha1", Kind: "Index"}
+	RegisterKind(gvk)
+	uid := types.UID("0b5c4f3e-0000-4000-8000-000000000001")
+	SetProviderConfig(uid, "ProviderConfig.opensearch.upbound.io/default")
+
+	var lines [][]any
+	l := WithReconcileKeys(logr.New(&recordingSink{lines: &lines}))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "logs"}}
+	l = l.WithValues("controller", managed.ControllerName(gvk.String()), "request", req)
+	l.Info("Reconciling", "uid", uid, "external-name", "logs-v1", "external-name", "logs-v2")
+
+	if len(lines) != 1 {
+		t.Fatalf("lines: want 1, got %d", len(lines))
+	}
+	got := map[any]any{}
+	keys := 0
+	for i := 0; i+1 < len(lines[0]); i += 2 {
+		got[lines[0][i]] = lines[0][i+1]
+		keys++
+	}
+	if keys != len(got) {
+		t.Errorf("keys: want each key once, got %v", lines[0])
+	}
+	if id, ok := got[KeyOperationID].(string); !ok || id == "" {
+		t.Errorf("%s: want an operation ID, got %v", KeyOperationID, got[KeyOperationID])
+	}
+	delete(got, KeyOperationID)
+	want := map[any]any{
+		"controller":      managed.ControllerName(gvk.String()),
+		KeyGVK:            gvk.String(),
+		KeyNamespace:      "team-a",
+		KeyName:           "logs",
+		"uid":             uid,
+		KeyExternalName:   "logs-v2",
+		KeyProviderConfig: "ProviderConfig.opensearch.upbound.io/default",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("key values: want %v, got %v", want, got)
+	}
+}
+
+func TestWithReconcileKeysOperationIDPerReconcile(t *testing.T) {
+	var lines [][]any
+	l := WithReconcileKeys(logr.New(&recordingSink{lines: &lines}))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "logs"}}
+	first := l.WithValues("request", req)
+	first.Info("Reconciling")
+	first.Info("Reconciled")
+	l.WithValues("request", req).Info("Reconciling")
+
+	ids := make([]any, 0, len(lines))
+	for _, line := range lines {
+		v, _ := value(line, KeyOperationID)
+		ids = append(ids, v)
+		if _, ok := value(line, KeyNamespace); ok {
+			t.Errorf("%s: want none for cluster-scoped resources, got %v", KeyNamespace, line)
+		}
+	}
+	if ids[0] != ids[1] {
+		t.Errorf("operation IDs of one reconcile differ: %v and %v", ids[0], ids[1])
+	}
+	if ids[0] == ids[2] {
+		t.Errorf("operation IDs of different reconciles are both %v", ids[0])
+	}
+}
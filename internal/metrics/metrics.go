@@ -0,0 +1,138 @@
+// Package metrics contains the Prometheus metrics of the provider.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	promNS             = "provider_opensearch"
+	promSysHTTP        = "http"
+	promSysResource    = "resource"
+	promSysProviderCfg = "providerconfig"
+	promSysAudit       = "audit"
+)
+
+var (
+	// BuildInfo is a gauge metric that is always 1 and carries the versions
+	// of the provider and of the Terraform components it runs with as
+	// labels.
+	BuildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNS,
+		Name:      "build_info",
+		Help:      "A metric with a constant '1' value labeled by the versions of the provider, Terraform and the Terraform provider.",
+	}, []string{"version", "terraform_version", "terraform_provider_version"})
+
+	// Requests is a counter metric of the number of HTTP requests the
+	// provider sent to OpenSearch with its own client, i.e. probes, the
+	// requests of the extensions of Terraform resources and audit records.
+	// The requests of the Terraform provider are not covered; see
+	// Operations instead. Requests that are not sent by an operation of a
+	// managed resource, e.g. probes, have an empty kind.
+	Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNS,
+		Subsystem: promSysHTTP,
+		Name:      "requests_total",
+		Help:      "The number of HTTP requests the provider sent to OpenSearch for probes, resource extensions and audit records. Requests of the Terraform provider are not included.",
+	}, []string{"method", "path", "code", "provider_config", "kind"})
+
+	// RequestDuration is the histogram metric of the latency of HTTP
+	// requests the provider sent to OpenSearch with its own client, like
+	// Requests.
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNS,
+		Subsystem: promSysHTTP,
+		Name:      "request_duration_seconds",
+		Help:      "Measures in seconds how long it takes OpenSearch to answer an HTTP request the provider sent for probes, resource extensions and audit records. Requests of the Terraform provider are not included.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "path", "code", "provider_config", "kind"})
+
+	// Operations is a counter metric of the number of operations the
+	// Terraform provider ran against OpenSearch. The Terraform provider
+	// builds its own HTTP clients, so its requests are measured per
+	// operation. The code is the HTTP status code OpenSearch answered a
+	// failed operation with, if known. It is parsed from the error, which
+	// the Terraform provider often wraps in an error of its own.
+	Operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNS,
+		Subsystem: promSysResource,
+		Name:      "operations_total",
+		Help:      "The number of create, read, update and delete operations run against OpenSearch.",
+	}, []string{"operation", "result", "code", "provider_config", "kind"})
+
+	// OperationDuration is the histogram metric of the duration of the
+	// operations the Terraform provider ran against OpenSearch.
+	OperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNS,
+		Subsystem: promSysResource,
+		Name:      "operation_duration_seconds",
+		Help:      "Measures in seconds how long it takes an operation against OpenSearch to complete.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+	}, []string{"operation", "result", "provider_config", "kind"})
+
+	// DriftDetected is a counter metric of the number of times a managed
+	// resource was updated because its resource in OpenSearch no longer
+	// had the state the provider last applied. Updates that apply a changed
+	// desired state are not counted.
+	DriftDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNS,
+		Subsystem: promSysResource,
+		Name:      "drift_detected_total",
+		Help:      "The number of times a resource in OpenSearch drifted from its desired state.",
+	}, []string{"provider_config", "kind"})
+
+	// AuditRecords is a counter metric of the number of audit records that
+	// were written to OpenSearch, failed an attempt to be written, or were
+	// dropped because too many were waiting to be written or retried.
+	AuditRecords = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNS,
+		Subsystem: promSysAudit,
+		Name:      "records_total",
+		Help:      "The number of audit records of changes written to OpenSearch, failed attempts to write them, which are retried, and records dropped before they were written.",
+	}, []string{"result"})
+
+	// OperationsWaiting is the number of operations that wait for the
+	// limits of a ProviderConfig.
+	OperationsWaiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNS,
+		Subsystem: promSysProviderCfg,
+		Name:      "operations_waiting",
+		Help:      "Number of operations waiting for the limits of a ProviderConfig.",
+	}, []string{"provider_config"})
+
+	// OperationsInFlight is the number of operations that run against the
+	// cluster of a ProviderConfig.
+	OperationsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNS,
+		Subsystem: promSysProviderCfg,
+		Name:      "operations_in_flight",
+		Help:      "Number of operations running against the cluster of a ProviderConfig.",
+	}, []string{"provider_config"})
+
+	// OperationWait is the histogram metric of the time operations waited
+	// for the limits of a ProviderConfig.
+	OperationWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNS,
+		Subsystem: promSysProviderCfg,
+		Name:      "operation_wait_seconds",
+		Help:      "Time operations waited for the limits of a ProviderConfig before they started.",
+		Buckets:   []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"provider_config"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(BuildInfo, Requests, RequestDuration, Operations, OperationDuration, DriftDetected, AuditRecords,
+		OperationsWaiting, OperationsInFlight, OperationWait)
+}
+
+// ForgetProviderConfig deletes the metrics of the supplied ProviderConfig.
+func ForgetProviderConfig(pc string) {
+	l := prometheus.Labels{"provider_config": pc}
+	for _, v := range []interface{ DeletePartialMatch(prometheus.Labels) int }{
+		Requests, RequestDuration, Operations, OperationDuration, DriftDetected,
+		OperationsWaiting, OperationsInFlight, OperationWait,
+	} {
+		v.DeletePartialMatch(l)
+	}
+}
@@ -0,0 +1,47 @@
+// Package version contains the version of this repo
+package version
+
+import "sync"
+
+// Version will be overridden with the current version at build time using
+// the -X linker flag
+var Version = "0.0.0"
+
+var (
+	mu                       sync.RWMutex
+	terraformVersion         string
+	terraformProviderVersion string
+)
+
+// Info is the version of the provider and of the Terraform components it
+// runs with.
+type Info struct {
+	// Version of the provider.
+	Version string
+
+	// TerraformVersion is the version of the Terraform CLI.
+	TerraformVersion string
+
+	// TerraformProviderVersion is the version of the Terraform provider.
+	TerraformProviderVersion string
+}
+
+// SetTerraform records the versions of the Terraform CLI and of the
+// Terraform provider the provider runs with. It is called once at startup.
+func SetTerraform(version, providerVersion string) {
+	mu.Lock()
+	defer mu.Unlock()
+	terraformVersion, terraformProviderVersion = version, providerVersion
+}
+
+// Get returns the version of the provider and of the Terraform components it
+// runs with.
+func Get() Info {
+	mu.RLock()
+	defer mu.RUnlock()
+	return Info{
+		Version:                  Version,
+		TerraformVersion:         terraformVersion,
+		TerraformProviderVersion: terraformProviderVersion,
+	}
+}